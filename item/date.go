@@ -2,9 +2,9 @@ package item
 
 import (
 	"encoding/json"
-	"fmt"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,8 +12,30 @@ const (
 	DateFormat = "2006-01-02"
 )
 
+var timezone atomic.Pointer[time.Location]
+
+// SetTimezone sets the timezone of the user. It determines what "today" is
+// and in which zone times without an explicit zone are interpreted.
+func SetTimezone(loc *time.Location) {
+	timezone.Store(loc)
+}
+
+// Timezone returns the timezone of the user, time.Local if none was set
+func Timezone() *time.Location {
+	if loc := timezone.Load(); loc != nil {
+		return loc
+	}
+
+	return time.Local
+}
+
 func Today() Date {
-	year, month, day := time.Now().Date()
+	return TodayIn(Timezone())
+}
+
+// TodayIn returns the current date as seen in loc
+func TodayIn(loc *time.Location) Date {
+	year, month, day := time.Now().In(loc).Date()
 	return NewDate(year, int(month), day)
 }
 
@@ -105,38 +127,10 @@ func NewDate(year, month, day int) Date {
 	}
 }
 
+// NewDateFromString parses a date relative to today. See ParseDate for the
+// formats that are understood.
 func NewDateFromString(date string) Date {
-	date = strings.ToLower(strings.TrimSpace(date))
-
-	switch date {
-	case "":
-		fallthrough
-	case "no-date":
-		fallthrough
-	case "no date":
-		return Date{}
-	case "today":
-		return Today()
-	case "tod":
-		return Today()
-	case "tomorrow":
-		return Today().AddDays(1)
-	case "tom":
-		return Today().AddDays(1)
-	}
-
-	t, err := time.Parse("2006-01-02", fmt.Sprintf("%.10s", date))
-	if err == nil {
-		return Date{t: t}
-	}
-
-	newWeekday, ok := ParseWeekday(date)
-	if !ok {
-		return Date{}
-	}
-	daysToAdd := findDaysToWeekday(Today().Weekday(), newWeekday)
-
-	return Today().Add(daysToAdd)
+	return ParseDate(date, Today())
 }
 
 func findDaysToWeekday(current, wanted time.Weekday) int {
@@ -148,21 +142,24 @@ func findDaysToWeekday(current, wanted time.Weekday) int {
 	return daysToAdd
 }
 
+// DaysBetween returns the number of days between d and d2, regardless of
+// which one comes first
 func (d Date) DaysBetween(d2 Date) int {
-	tDate := d2
-	end := d
-	if !end.After(tDate) {
-		end = d2
-		tDate = d
+	days := (d.t.Unix() - d2.t.Unix()) / (24 * 60 * 60)
+	if days < 0 {
+		days = -days
 	}
 
-	days := 0
-	for {
-		if tDate.Add(days).Equal(end) {
-			return days
-		}
-		days++
-	}
+	return int(days)
+}
+
+// MonthsBetween returns the number of months from the month of d to the
+// month of d2, ignoring the days
+func (d Date) MonthsBetween(d2 Date) int {
+	y1, m1, _ := d.t.Date()
+	y2, m2, _ := d2.t.Date()
+
+	return (y2-y1)*12 + int(m2) - int(m1)
 }
 
 func (d Date) String() string {
@@ -205,22 +202,16 @@ func (d Date) Day() int {
 }
 
 func (d Date) Add(days int) Date {
-	year, month, day := d.t.Date()
-	return NewDate(year, int(month), day+days)
+	return Date{t: d.t.AddDate(0, 0, days)}
 }
 
+// AddMonths moves d the given amount of months. A day that does not exist
+// in the new month overflows into the next one.
 func (d Date) AddMonths(addMonths int) Date {
-	year, mmonth, day := d.t.Date()
-	month := int(mmonth)
-	for m := 1; m <= addMonths; m++ {
-		month += 1
-		if month == 12 {
-			year += 1
-			month = 1
-		}
-	}
+	year, month, day := d.t.Date()
+	months := year*12 + int(month) - 1 + addMonths
 
-	return NewDate(year, month, day)
+	return NewDate(months/12, months%12+1, day)
 }
 
 func (d Date) Equal(ud Date) bool {
@@ -233,9 +224,7 @@ func (d Date) After(ud Date) bool {
 }
 
 func (d Date) AddDays(amount int) Date {
-	year, month, date := d.t.Date()
-
-	return NewDate(year, int(month), date+amount)
+	return d.Add(amount)
 }
 
 func ParseWeekday(wd string) (time.Weekday, bool) {
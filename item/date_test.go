@@ -174,6 +174,24 @@ func TestNewDateFromString(t *testing.T) {
 	})
 }
 
+func TestTodayIn(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"Pacific/Kiritimati", "UTC", "Pacific/Pago_Pago"} {
+		t.Run(name, func(t *testing.T) {
+			loc, err := time.LoadLocation(name)
+			if err != nil {
+				t.Fatalf("exp nil, got %v", err)
+			}
+			year, month, day := time.Now().In(loc).Date()
+			exp := item.NewDate(year, int(month), day)
+			if act := item.TodayIn(loc); !exp.Equal(act) {
+				t.Errorf("exp %v, got %v", exp, act)
+			}
+		})
+	}
+}
+
 func TestDateDaysBetween(t *testing.T) {
 	t.Parallel()
 
@@ -212,6 +230,12 @@ func TestDateDaysBetween(t *testing.T) {
 			d2:   item.NewDate(2021, 5, 23),
 			exp:  31,
 		},
+		{
+			name: "centuries",
+			d1:   item.NewDate(1900, 1, 1),
+			d2:   item.NewDate(2300, 1, 1),
+			exp:  146097,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			if tc.exp != tc.d1.DaysBetween(tc.d2) {
@@ -221,6 +245,119 @@ func TestDateDaysBetween(t *testing.T) {
 	}
 }
 
+func TestDateMonthsBetween(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		d1   item.Date
+		d2   item.Date
+		exp  int
+	}{
+		{
+			name: "same month",
+			d1:   item.NewDate(2021, 6, 23),
+			d2:   item.NewDate(2021, 6, 1),
+		},
+		{
+			name: "over year",
+			d1:   item.NewDate(2021, 11, 30),
+			d2:   item.NewDate(2022, 2, 1),
+			exp:  3,
+		},
+		{
+			name: "reverse",
+			d1:   item.NewDate(2022, 2, 1),
+			d2:   item.NewDate(2021, 11, 30),
+			exp:  -3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.exp != tc.d1.MonthsBetween(tc.d2) {
+				t.Errorf("exp %v, got %v", tc.exp, tc.d1.MonthsBetween(tc.d2))
+			}
+		})
+	}
+}
+
+func TestDateAdd(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		date item.Date
+		days int
+		exp  item.Date
+	}{
+		{
+			name: "forward",
+			date: item.NewDate(2024, 12, 30),
+			days: 3,
+			exp:  item.NewDate(2025, 1, 2),
+		},
+		{
+			name: "back to previous month",
+			date: item.NewDate(2024, 3, 1),
+			days: -1,
+			exp:  item.NewDate(2024, 2, 29),
+		},
+		{
+			name: "back to previous year",
+			date: item.NewDate(2025, 1, 1),
+			days: -2,
+			exp:  item.NewDate(2024, 12, 30),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if act := tc.date.Add(tc.days); !tc.exp.Equal(act) {
+				t.Errorf("exp %v, got %v", tc.exp, act)
+			}
+		})
+	}
+}
+
+func TestDateAddMonths(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		date   item.Date
+		months int
+		exp    item.Date
+	}{
+		{
+			name:   "into december",
+			date:   item.NewDate(2024, 11, 15),
+			months: 1,
+			exp:    item.NewDate(2024, 12, 15),
+		},
+		{
+			name:   "over year",
+			date:   item.NewDate(2024, 11, 15),
+			months: 3,
+			exp:    item.NewDate(2025, 2, 15),
+		},
+		{
+			name:   "many years",
+			date:   item.NewDate(1900, 1, 15),
+			months: 1200,
+			exp:    item.NewDate(2000, 1, 15),
+		},
+		{
+			name:   "overflow",
+			date:   item.NewDate(2023, 1, 31),
+			months: 1,
+			exp:    item.NewDate(2023, 3, 3),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if act := tc.date.AddMonths(tc.months); !tc.exp.Equal(act) {
+				t.Errorf("exp %v, got %v", tc.exp, act)
+			}
+		})
+	}
+}
+
 func TestDateString(t *testing.T) {
 	for _, tc := range []struct {
 		name string
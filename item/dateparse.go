@@ -0,0 +1,227 @@
+package item
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var monthNames = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+// ParseDate parses a date, with ref as the date that "today" refers to.
+// Besides yyyy-mm-dd it understands:
+//
+//   - today, tomorrow, yesterday, or tod and tom
+//   - a weekday, which is the first one after ref
+//   - next monday, which is the monday in the week after that of ref
+//   - next week, next month, next year, which are the first day of it
+//   - end of week, end of month, end of year, or eow, eom and eoy
+//   - in 3 days, in 2 weeks, in a month, in 1 year
+//   - +3d, +2w, +1m, +1y, and the same with a minus
+//   - dec 24, 24 december, 24/12, which are the first one on or after ref,
+//     optionally followed by a year, as in dec 24 2025 or 24/12/2025. A
+//     month can only be a number in the form with slashes, so 1 2 is not a
+//     date.
+//
+// Weeks start on monday. Moving by months or years stays within the month,
+// so jan 31 plus one month is the last day of february. The zero date is
+// returned if the string cannot be parsed.
+func ParseDate(date string, ref Date) Date {
+	date = strings.Join(strings.Fields(strings.ToLower(date)), " ")
+
+	switch date {
+	case "", "no-date", "no date":
+		return Date{}
+	case "today", "tod":
+		return ref
+	case "tomorrow", "tom":
+		return ref.Add(1)
+	case "yesterday":
+		return ref.Add(-1)
+	case "next week":
+		return startOfWeek(ref).Add(7)
+	case "next month":
+		return addMonthsInMonth(ref, 1).firstOfMonth()
+	case "next year":
+		return NewDate(ref.t.Year()+1, 1, 1)
+	case "end of week", "eow":
+		return startOfWeek(ref).Add(6)
+	case "end of month", "eom":
+		return ref.firstOfMonth().AddMonths(1).Add(-1)
+	case "end of year", "eoy":
+		return NewDate(ref.t.Year(), 12, 31)
+	}
+
+	t, err := time.Parse(DateFormat, fmt.Sprintf("%.10s", date))
+	if err == nil {
+		return Date{t: t}
+	}
+
+	if wd, ok := ParseWeekday(date); ok {
+		return ref.Add(findDaysToWeekday(ref.Weekday(), wd))
+	}
+	if wdStr, ok := strings.CutPrefix(date, "next "); ok {
+		if wd, ok := ParseWeekday(wdStr); ok {
+			return startOfWeek(ref).Add(7 + (int(wd)+6)%7)
+		}
+		return Date{}
+	}
+	if offset, ok := strings.CutPrefix(date, "in "); ok {
+		return parseOffset(ref, strings.Fields(offset))
+	}
+	if strings.HasPrefix(date, "+") || strings.HasPrefix(date, "-") {
+		return parseShortOffset(ref, date)
+	}
+
+	return parseDayMonth(ref, date)
+}
+
+func startOfWeek(d Date) Date {
+	return d.Add(-((int(d.Weekday()) + 6) % 7))
+}
+
+func (d Date) firstOfMonth() Date {
+	return NewDate(d.t.Year(), int(d.t.Month()), 1)
+}
+
+// addMonthsInMonth is like AddMonths, but uses the last day of the month
+// instead of overflowing into the next one
+func addMonthsInMonth(d Date, months int) Date {
+	first := d.firstOfMonth().AddMonths(months)
+	day := min(d.Day(), daysIn(first.t.Year(), first.t.Month()))
+
+	return first.Add(day - 1)
+}
+
+// in 3 days, in a week
+func parseOffset(ref Date, terms []string) Date {
+	if len(terms) != 2 {
+		return Date{}
+	}
+	var n int
+	switch terms[0] {
+	case "a", "an", "one":
+		n = 1
+	default:
+		var err error
+		if n, err = strconv.Atoi(terms[0]); err != nil || n < 0 {
+			return Date{}
+		}
+	}
+
+	switch terms[1] {
+	case "day", "days":
+		return ref.Add(n)
+	case "week", "weeks":
+		return ref.Add(7 * n)
+	case "month", "months":
+		return addMonthsInMonth(ref, n)
+	case "year", "years":
+		return addMonthsInMonth(ref, 12*n)
+	default:
+		return Date{}
+	}
+}
+
+// +3d, -1w
+func parseShortOffset(ref Date, date string) Date {
+	if len(date) < 3 {
+		return Date{}
+	}
+	n, err := strconv.Atoi(date[:len(date)-1])
+	if err != nil {
+		return Date{}
+	}
+
+	switch date[len(date)-1] {
+	case 'd':
+		return ref.Add(n)
+	case 'w':
+		return ref.Add(7 * n)
+	case 'm':
+		return addMonthsInMonth(ref, n)
+	case 'y':
+		return addMonthsInMonth(ref, 12*n)
+	default:
+		return Date{}
+	}
+}
+
+// dec 24, 24 dec 2025, 24/12, 24/12/2025
+func parseDayMonth(ref Date, date string) Date {
+	var dayStr, monthStr, yearStr string
+	// without slashes the month has to be a name
+	var spaced bool
+	if parts := strings.Split(date, "/"); len(parts) == 2 || len(parts) == 3 {
+		dayStr, monthStr = parts[0], parts[1]
+		if len(parts) == 3 {
+			yearStr = parts[2]
+		}
+	} else {
+		terms := strings.Fields(date)
+		if len(terms) != 2 && len(terms) != 3 {
+			return Date{}
+		}
+		if _, ok := monthNames[terms[0]]; ok {
+			monthStr, dayStr = terms[0], terms[1]
+		} else {
+			dayStr, monthStr = terms[0], terms[1]
+		}
+		spaced = true
+		if len(terms) == 3 {
+			yearStr = terms[2]
+		}
+	}
+
+	day, err := strconv.Atoi(strings.TrimRight(dayStr, "stndrh"))
+	if err != nil {
+		return Date{}
+	}
+	month, ok := monthNames[monthStr]
+	if !ok {
+		if spaced {
+			return Date{}
+		}
+		m, err := strconv.Atoi(monthStr)
+		if err != nil || m < 1 || m > 12 {
+			return Date{}
+		}
+		month = time.Month(m)
+	}
+	if day < 1 || day > daysIn(2024, month) {
+		return Date{}
+	}
+
+	if yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil || len(yearStr) != 4 || day > daysIn(year, month) {
+			return Date{}
+		}
+		return NewDate(year, int(month), day)
+	}
+
+	// the first one on or after ref, which for february 29 can be a few
+	// years away
+	for year := ref.t.Year(); ; year++ {
+		if day > daysIn(year, month) {
+			continue
+		}
+		if d := NewDate(year, int(month), day); !ref.After(d) {
+			return d
+		}
+	}
+}
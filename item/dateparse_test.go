@@ -0,0 +1,85 @@
+package item_test
+
+import (
+	"testing"
+
+	"go-mod.ewintr.nl/planner/item"
+)
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+
+	wednesday := item.NewDate(2024, 1, 31)
+	for _, tc := range []struct {
+		name  string
+		ref   item.Date
+		input string
+		exp   item.Date
+	}{
+		{name: "empty"},
+		{name: "no date", input: "no date"},
+		{name: "iso", input: "2025-03-04", exp: item.NewDate(2025, 3, 4)},
+		{name: "today", input: "today", exp: wednesday},
+		{name: "tod", input: "tod", exp: wednesday},
+		{name: "tomorrow", input: "tomorrow", exp: item.NewDate(2024, 2, 1)},
+		{name: "yesterday", input: "yesterday", exp: item.NewDate(2024, 1, 30)},
+		{name: "weekday", input: "monday", exp: item.NewDate(2024, 2, 5)},
+		{name: "same weekday", input: "Wed", exp: item.NewDate(2024, 2, 7)},
+		{name: "next monday", input: "next monday", exp: item.NewDate(2024, 2, 5)},
+		{name: "next wednesday", input: "next wednesday", exp: item.NewDate(2024, 2, 7)},
+		{name: "next sunday", input: "next sun", exp: item.NewDate(2024, 2, 11)},
+		{name: "next week", input: "next week", exp: item.NewDate(2024, 2, 5)},
+		{name: "next month", input: "next month", exp: item.NewDate(2024, 2, 1)},
+		{
+			name:  "next month in december",
+			ref:   item.NewDate(2024, 12, 15),
+			input: "next month",
+			exp:   item.NewDate(2025, 1, 1),
+		},
+		{name: "next year", input: "next year", exp: item.NewDate(2025, 1, 1)},
+		{name: "end of week", input: "end of week", exp: item.NewDate(2024, 2, 4)},
+		{name: "eom", input: "eom", exp: wednesday},
+		{name: "end of month", ref: item.NewDate(2024, 2, 10), input: "end of month", exp: item.NewDate(2024, 2, 29)},
+		{name: "end of year", input: "eoy", exp: item.NewDate(2024, 12, 31)},
+		{name: "in days", input: "in 3 days", exp: item.NewDate(2024, 2, 3)},
+		{name: "in a week", input: "in a week", exp: item.NewDate(2024, 2, 7)},
+		{name: "in weeks", input: "in  2 weeks", exp: item.NewDate(2024, 2, 14)},
+		{name: "in a month", input: "in 1 month", exp: item.NewDate(2024, 2, 29)},
+		{name: "in a year", input: "in 1 year", exp: item.NewDate(2025, 1, 31)},
+		{name: "plus days", input: "+3d", exp: item.NewDate(2024, 2, 3)},
+		{name: "plus weeks", input: "+2w", exp: item.NewDate(2024, 2, 14)},
+		{name: "plus months", input: "+1m", exp: item.NewDate(2024, 2, 29)},
+		{name: "plus years", input: "+1y", exp: item.NewDate(2025, 1, 31)},
+		{name: "minus days", input: "-1d", exp: item.NewDate(2024, 1, 30)},
+		{name: "month day", input: "dec 24", exp: item.NewDate(2024, 12, 24)},
+		{name: "day month", input: "24 december", exp: item.NewDate(2024, 12, 24)},
+		{name: "ordinal", input: "december 24th", exp: item.NewDate(2024, 12, 24)},
+		{name: "slash", input: "24/12", exp: item.NewDate(2024, 12, 24)},
+		{name: "passed", input: "jan 15", exp: item.NewDate(2025, 1, 15)},
+		{name: "on ref", input: "31/1", exp: wednesday},
+		{name: "with year", input: "dec 24 2025", exp: item.NewDate(2025, 12, 24)},
+		{name: "slash with year", input: "24/12/2025", exp: item.NewDate(2025, 12, 24)},
+		{name: "leap day", input: "feb 29", exp: item.NewDate(2024, 2, 29)},
+		{name: "next leap day", ref: item.NewDate(2024, 3, 1), input: "feb 29", exp: item.NewDate(2028, 2, 29)},
+		{name: "no leap year", input: "29 feb 2025"},
+		{name: "invalid day", input: "32/12"},
+		{name: "invalid month", input: "13/13"},
+		{name: "numbers", input: "1 2"},
+		{name: "numbers with year", input: "24 12 2025"},
+		{name: "only month", input: "dec"},
+		{name: "invalid unit", input: "in 3 lightyears"},
+		{name: "invalid short unit", input: "+3x"},
+		{name: "next unknown", input: "next thing"},
+		{name: "unknown", input: "someday"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ref := tc.ref
+			if ref.IsZero() {
+				ref = wednesday
+			}
+			if act := item.ParseDate(tc.input, ref); !tc.exp.Equal(act) {
+				t.Errorf("exp %v, got %v", tc.exp.String(), act.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package item
+
+// Overdue tells whether the deadline of the task has passed
+func (t Task) Overdue(today Date) bool {
+	return !t.Deadline.IsZero() && today.After(t.Deadline)
+}
+
+// DeadlineWithin tells whether the deadline of the task is today or in the
+// given number of days after it
+func (t Task) DeadlineWithin(today Date, days int) bool {
+	if t.Deadline.IsZero() || t.Overdue(today) {
+		return false
+	}
+
+	return !t.Deadline.After(today.Add(days))
+}
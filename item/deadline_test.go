@@ -0,0 +1,52 @@
+package item_test
+
+import (
+	"testing"
+
+	"go-mod.ewintr.nl/planner/item"
+)
+
+func TestTaskDeadline(t *testing.T) {
+	t.Parallel()
+
+	today := item.NewDate(2024, 6, 1)
+	for _, tc := range []struct {
+		name       string
+		deadline   item.Date
+		expOverdue bool
+		expWithin  bool
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:       "passed",
+			deadline:   item.NewDate(2024, 5, 31),
+			expOverdue: true,
+		},
+		{
+			name:      "today",
+			deadline:  today,
+			expWithin: true,
+		},
+		{
+			name:      "end of window",
+			deadline:  item.NewDate(2024, 6, 4),
+			expWithin: true,
+		},
+		{
+			name:     "after window",
+			deadline: item.NewDate(2024, 6, 5),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tsk := item.Task{TaskBody: item.TaskBody{Deadline: tc.deadline}}
+			if act := tsk.Overdue(today); act != tc.expOverdue {
+				t.Errorf("exp %v, got %v", tc.expOverdue, act)
+			}
+			if act := tsk.DeadlineWithin(today, 3); act != tc.expWithin {
+				t.Errorf("exp %v, got %v", tc.expWithin, act)
+			}
+		})
+	}
+}
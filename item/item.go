@@ -3,6 +3,7 @@ package item
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -22,17 +23,23 @@ const (
 
 var (
 	KnownKinds = []Kind{KindSchedule, KindTask}
+
+	instanceNamespace = uuid.MustParse("ace71052-3d4f-40c6-a0c5-eabca289efbc")
 )
 
 type Item struct {
-	ID        string    `json:"id"`
-	Kind      Kind      `json:"kind"`
-	Updated   time.Time `json:"updated"`
-	Deleted   bool      `json:"deleted"`
-	Date      Date      `json:"date"`
-	Recurrer  Recurrer  `json:"recurrer"`
-	RecurNext Date      `json:"recurNext"`
-	Body      string    `json:"body"`
+	ID          string    `json:"id"`
+	Kind        Kind      `json:"kind"`
+	Updated     time.Time `json:"updated"`
+	Deleted     bool      `json:"deleted"`
+	Date        Date      `json:"date"`
+	Recurrer    Recurrer  `json:"recurrer"`
+	RecurNext   Date      `json:"recurNext"`
+	RecurParent string    `json:"recurParent"`
+	// RewriteFrom asks the server to apply a changed or deleted recurring
+	// item to the instances it already spawned on or after this date
+	RewriteFrom Date   `json:"rewriteFrom"`
+	Body        string `json:"body"`
 }
 
 func (i Item) MarshalJSON() ([]byte, error) {
@@ -75,6 +82,13 @@ func NewItem(k Kind, body string) Item {
 	}
 }
 
+// InstanceID derives the id of the instance that a recurring item spawns on
+// date. It is the same every time, so an instance that was spawned before
+// can be recognized and is not created twice.
+func InstanceID(parentID string, date Date) string {
+	return uuid.NewSHA1(instanceNamespace, []byte(fmt.Sprintf("%s/%s", parentID, date.String()))).String()
+}
+
 func ItemDiff(exp, got Item) string {
 	expJSON, _ := json.Marshal(exp)
 	actJSON, _ := json.Marshal(got)
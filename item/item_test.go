@@ -32,20 +32,24 @@ func TestItemJSON(t *testing.T) {
   "deleted": false,
   "date": "",
   "recurNext": "",
+  "recurParent": "",
+  "rewriteFrom": "",
   "body": "{\"title\":\"title\"}"
 }`,
 		},
 		{
 			name: "full",
 			item: item.Item{
-				ID:        "a",
-				Kind:      item.KindTask,
-				Updated:   time.Date(2024, 12, 25, 11, 9, 0, 0, time.UTC),
-				Deleted:   true,
-				Date:      item.NewDate(2024, 12, 26),
-				Recurrer:  item.NewRecurrer("2024-12-25, daily"),
-				RecurNext: item.NewDateFromString("2024-12-30"),
-				Body:      `{"title":"title"}`,
+				ID:          "a",
+				Kind:        item.KindTask,
+				Updated:     time.Date(2024, 12, 25, 11, 9, 0, 0, time.UTC),
+				Deleted:     true,
+				Date:        item.NewDate(2024, 12, 26),
+				Recurrer:    item.NewRecurrer("2024-12-25, daily"),
+				RecurNext:   item.NewDateFromString("2024-12-30"),
+				RecurParent: "b",
+				RewriteFrom: item.NewDate(2024, 12, 27),
+				Body:        `{"title":"title"}`,
 			},
 			expJSON: `{
   "recurrer": "2024-12-25, daily",
@@ -55,6 +59,8 @@ func TestItemJSON(t *testing.T) {
   "deleted": true,
   "date": "2024-12-26",
   "recurNext": "2024-12-30",
+  "recurParent": "b",
+  "rewriteFrom": "2024-12-27",
   "body": "{\"title\":\"title\"}"
 }`,
 		},
@@ -81,3 +87,19 @@ func TestItemJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestInstanceID(t *testing.T) {
+	t.Parallel()
+
+	date := item.NewDate(2024, 12, 25)
+	id := item.InstanceID("parent", date)
+	if id != item.InstanceID("parent", date) {
+		t.Errorf("exp same id for same parent and date")
+	}
+	if id == item.InstanceID("parent", date.Add(1)) {
+		t.Errorf("exp different id for different date")
+	}
+	if id == item.InstanceID("other", date) {
+		t.Errorf("exp different id for different parent")
+	}
+}
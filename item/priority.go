@@ -0,0 +1,87 @@
+package item
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Priority runs from p1, the highest, to p4, the lowest. Zero means no
+// priority was set.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	Priority1
+	Priority2
+	Priority3
+	Priority4
+)
+
+// ParsePriority accepts p1 to p4, or just the number. An empty string
+// clears the priority.
+func ParsePriority(s string) (Priority, error) {
+	s = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "p")
+	if s == "" {
+		return PriorityNone, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < int(Priority1) || n > int(Priority4) {
+		return PriorityNone, fmt.Errorf("priority must be one of p1, p2, p3 or p4")
+	}
+
+	return Priority(n), nil
+}
+
+func (p Priority) String() string {
+	if p == PriorityNone {
+		return ""
+	}
+
+	return fmt.Sprintf("p%d", p)
+}
+
+const (
+	urgencyDueDays = 14
+	urgencyMaxAge  = 365
+)
+
+// Urgency scores how pressing a task is on the given day, the higher the
+// more urgent. It adds up the priority (p1 6, p2 4, p3 2, others 0), the
+// date or the deadline, whichever is more pressing (12 when passed, 10 for
+// today, falling to 0 over the next two weeks) and the age (up to 2 after a
+// year).
+func (t Task) Urgency(today Date) float64 {
+	var urgency float64
+	switch t.Priority {
+	case Priority1:
+		urgency += 6
+	case Priority2:
+		urgency += 4
+	case Priority3:
+		urgency += 2
+	}
+
+	urgency += max(dueUrgency(t.Date, today), dueUrgency(t.Deadline, today))
+
+	if !t.Created.IsZero() && today.After(t.Created) {
+		age := min(today.DaysBetween(t.Created), urgencyMaxAge)
+		urgency += 2 * float64(age) / urgencyMaxAge
+	}
+
+	return urgency
+}
+
+func dueUrgency(due, today Date) float64 {
+	if due.IsZero() {
+		return 0
+	}
+	switch days := today.DaysBetween(due); {
+	case today.After(due):
+		return 12
+	case days < urgencyDueDays:
+		return 10 * float64(urgencyDueDays-days) / urgencyDueDays
+	}
+
+	return 0
+}
@@ -0,0 +1,115 @@
+package item_test
+
+import (
+	"testing"
+
+	"go-mod.ewintr.nl/planner/item"
+)
+
+func TestParsePriority(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		input  string
+		exp    item.Priority
+		expStr string
+		expErr bool
+	}{
+		{input: "", exp: item.PriorityNone},
+		{input: "p1", exp: item.Priority1, expStr: "p1"},
+		{input: "P2", exp: item.Priority2, expStr: "p2"},
+		{input: "3", exp: item.Priority3, expStr: "p3"},
+		{input: " p4 ", exp: item.Priority4, expStr: "p4"},
+		{input: "p0", expErr: true},
+		{input: "p5", expErr: true},
+		{input: "high", expErr: true},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			act, err := item.ParsePriority(tc.input)
+			if tc.expErr != (err != nil) {
+				t.Errorf("exp %v, got %v", tc.expErr, err)
+			}
+			if act != tc.exp {
+				t.Errorf("exp %v, got %v", tc.exp, act)
+			}
+			if act.String() != tc.expStr {
+				t.Errorf("exp %v, got %v", tc.expStr, act.String())
+			}
+		})
+	}
+}
+
+func TestTaskUrgency(t *testing.T) {
+	t.Parallel()
+
+	today := item.NewDate(2024, 6, 1)
+	for _, tc := range []struct {
+		name string
+		tsk  item.Task
+		exp  float64
+	}{
+		{
+			name: "nothing",
+		},
+		{
+			name: "priority",
+			tsk:  item.Task{TaskBody: item.TaskBody{Priority: item.Priority1}},
+			exp:  6,
+		},
+		{
+			name: "low priority",
+			tsk:  item.Task{TaskBody: item.TaskBody{Priority: item.Priority4}},
+			exp:  0,
+		},
+		{
+			name: "overdue",
+			tsk:  item.Task{Date: item.NewDate(2024, 5, 1)},
+			exp:  12,
+		},
+		{
+			name: "today",
+			tsk:  item.Task{Date: today},
+			exp:  10,
+		},
+		{
+			name: "in a week",
+			tsk:  item.Task{Date: item.NewDate(2024, 6, 8)},
+			exp:  5,
+		},
+		{
+			name: "far away",
+			tsk:  item.Task{Date: item.NewDate(2024, 8, 1)},
+			exp:  0,
+		},
+		{
+			name: "deadline",
+			tsk: item.Task{
+				Date:     item.NewDate(2024, 8, 1),
+				TaskBody: item.TaskBody{Deadline: item.NewDate(2024, 5, 31)},
+			},
+			exp: 12,
+		},
+		{
+			name: "old",
+			tsk:  item.Task{TaskBody: item.TaskBody{Created: item.NewDate(2020, 1, 1)}},
+			exp:  2,
+		},
+		{
+			name: "all",
+			tsk: item.Task{
+				Date: today,
+				TaskBody: item.TaskBody{
+					Priority: item.Priority2,
+					Created:  item.NewDate(2024, 6, 1).Add(-73),
+				},
+			},
+			exp: 4 + 10 + 0.4,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if act := tc.tsk.Urgency(today); act != tc.exp {
+				t.Errorf("exp %v, got %v", tc.exp, act)
+			}
+		})
+	}
+}
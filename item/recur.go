@@ -2,23 +2,35 @@ package item
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Recurrer interface {
 	RecursOn(date Date) bool
 	First() Date
+	// Next returns the first date after the given one on which it recurs,
+	// or a zero date if there is none
+	Next(after Date) Date
 	String() string
 }
 
+// NewRecurrer parses a recurrer. It can end with "until yyyy-mm-dd" or
+// "n times" to limit the recurrence, and after that with
+// "except yyyy-mm-dd & yyyy-mm-dd" to skip dates.
 func NewRecurrer(recurStr string) Recurrer {
+	return newRecurrer(recurStr, Today())
+}
+
+func newRecurrer(recurStr string, ref Date) Recurrer {
 	terms := strings.Split(recurStr, ",")
 	if len(terms) < 2 {
 		return nil
 	}
 
-	start := NewDateFromString(terms[0])
+	start := ParseDate(terms[0], ref)
 	if start.IsZero() {
 		return nil
 	}
@@ -28,11 +40,35 @@ func NewRecurrer(recurStr string) Recurrer {
 		terms[i] = strings.TrimSpace(t)
 	}
 
+	except, hasExcept := parseExcept(terms[len(terms)-1])
+	if hasExcept {
+		if len(terms) < 2 || len(except.Dates) == 0 {
+			return nil
+		}
+		terms = terms[:len(terms)-1]
+	}
+	end, hasEnd := parseEnding(terms[len(terms)-1])
+	if hasEnd {
+		if len(terms) < 2 || (end.Until.IsZero() && end.Count < 1) || end.Count > MaxCount {
+			return nil
+		}
+		terms = terms[:len(terms)-1]
+	}
+
 	for _, parseFunc := range []func(Date, []string) (Recurrer, bool){
 		ParseDaily, ParseEveryNDays, ParseWeekly,
 		ParseEveryNWeeks, ParseEveryNMonths,
+		ParseMonthlyWeekday, ParseMonthlyLastDay,
+		ParseYearly, ParseEveryWeekday,
 	} {
-		if recur, ok := parseFunc(start, terms); ok {
+		if recur, found := parseFunc(start, terms); found {
+			if hasEnd {
+				recur = NewEnding(recur, end.Until, end.Count)
+			}
+			if hasExcept {
+				except.Recurrer = recur
+				recur = except
+			}
 			return recur
 		}
 	}
@@ -40,16 +76,208 @@ func NewRecurrer(recurStr string) Recurrer {
 	return nil
 }
 
-func FirstRecurAfter(r Recurrer, d Date) Date {
-	lim := NewDate(2050, 1, 1)
+// from returns the first date to consider for a recurrence after the given
+// date, which is never before the start
+func from(start, after Date) Date {
+	if start.After(after) {
+		return start
+	}
+
+	return after.Add(1)
+}
+
+// Occurrences returns at most n dates on which r recurs, starting at the
+// given date. There are fewer when the recurrer ends before that.
+func Occurrences(r Recurrer, start Date, n int) []Date {
+	dates := make([]Date, 0, n)
+	for d := r.Next(start.Add(-1)); !d.IsZero() && len(dates) < n; d = r.Next(d) {
+		dates = append(dates, d)
+	}
+
+	return dates
+}
+
+// MaxCount is the highest number of times a recurrer can be limited to.
+// Finding the final recurrence walks all of them, so parsing a larger count
+// fails.
+const MaxCount = 1000
+
+// Ending limits a recurrer to the dates up until a given date, or to a
+// number of times. Create it with NewEnding.
+type Ending struct {
+	Recurrer Recurrer
+	Until    Date
+	Count    int
+	// End is the date of the final recurrence
+	End Date
+}
+
+// NewEnding limits rec to the dates up until until, or to count times, with
+// count at most MaxCount. The final recurrence is found once here, so that
+// checking a date does not have to walk all the recurrences again.
+func NewEnding(rec Recurrer, until Date, count int) Ending {
+	e := Ending{Recurrer: rec, Until: until, Count: count}
+	e.End = e.last()
+
+	return e
+}
+
+// until yyyy-mm-dd, or: 10 times
+func parseEnding(term string) (Ending, bool) {
+	if dateStr, ok := strings.CutPrefix(term, "until "); ok {
+		return Ending{Until: NewDateFromString(strings.TrimSpace(dateStr))}, true
+	}
+	if countStr, ok := strings.CutSuffix(term, " times"); ok {
+		count, err := strconv.Atoi(strings.TrimSpace(countStr))
+		if err != nil {
+			return Ending{}, true
+		}
+		return Ending{Count: count}, true
+	}
+
+	return Ending{}, false
+}
+
+func (e Ending) RecursOn(date Date) bool {
+	if !e.Recurrer.RecursOn(date) {
+		return false
+	}
+	last := e.Last()
+
+	return !last.IsZero() && !date.After(last)
+}
+
+func (e Ending) First() Date {
+	first := e.Recurrer.First()
+	if first.IsZero() || first.After(e.Last()) {
+		return Date{}
+	}
+
+	return first
+}
+
+func (e Ending) Next(after Date) Date {
+	next := e.Recurrer.Next(after)
+	if next.IsZero() || next.After(e.Last()) {
+		return Date{}
+	}
+
+	return next
+}
+
+// Last returns the date of the final recurrence
+func (e Ending) Last() Date {
+	return e.End
+}
+
+func (e Ending) last() Date {
+	if e.Count == 0 {
+		return e.Until
+	}
+
+	d := e.Recurrer.First()
+	for i := 1; i < e.Count && !d.IsZero(); i++ {
+		d = e.Recurrer.Next(d)
+	}
+	if !e.Until.IsZero() && d.After(e.Until) {
+		return e.Until
+	}
+
+	return d
+}
+
+func (e Ending) String() string {
+	if e.Count > 0 {
+		return fmt.Sprintf("%s, %d times", e.Recurrer.String(), e.Count)
+	}
+
+	return fmt.Sprintf("%s, until %s", e.Recurrer.String(), e.Until.String())
+}
+
+// Except skips a recurrer on specific dates, e.g. on holidays
+type Except struct {
+	Recurrer Recurrer
+	Dates    []Date
+}
+
+// except yyyy-mm-dd & yyyy-mm-dd
+func parseExcept(term string) (Except, bool) {
+	datesStr, ok := strings.CutPrefix(term, "except ")
+	if !ok {
+		return Except{}, false
+	}
+
+	dates := make([]Date, 0)
+	for _, dStr := range strings.Split(datesStr, "&") {
+		d := NewDateFromString(strings.TrimSpace(dStr))
+		if d.IsZero() {
+			return Except{}, true
+		}
+		dates = append(dates, d)
+	}
+
+	return Except{Dates: sortDates(dates)}, true
+}
+
+// WithException returns r extended to skip date d
+func WithException(r Recurrer, d Date) Recurrer {
+	if e, ok := r.(Except); ok {
+		e.Dates = sortDates(append(slices.Clone(e.Dates), d))
+		return e
+	}
+
+	return Except{
+		Recurrer: r,
+		Dates:    []Date{d},
+	}
+}
+
+// Skips reports whether date d is one of the exceptions
+func (e Except) Skips(d Date) bool {
+	return slices.ContainsFunc(e.Dates, d.Equal)
+}
+
+func (e Except) RecursOn(date Date) bool {
+	return !e.Skips(date) && e.Recurrer.RecursOn(date)
+}
+
+func (e Except) First() Date {
+	first := e.Recurrer.First()
+	if first.IsZero() || !e.Skips(first) {
+		return first
+	}
+
+	return e.Next(first)
+}
+
+func (e Except) Next(after Date) Date {
 	for {
-		d = d.Add(1)
-		if r.RecursOn(d) || d.Equal(lim) {
-			return d
+		after = e.Recurrer.Next(after)
+		if after.IsZero() || !e.Skips(after) {
+			return after
 		}
 	}
 }
 
+func (e Except) String() string {
+	dateStrs := make([]string, 0, len(e.Dates))
+	for _, d := range e.Dates {
+		dateStrs = append(dateStrs, d.String())
+	}
+
+	return fmt.Sprintf("%s, except %s", e.Recurrer.String(), strings.Join(dateStrs, " & "))
+}
+
+func sortDates(dates []Date) []Date {
+	slices.SortFunc(dates, func(a, b Date) int {
+		return a.Time().Compare(b.Time())
+	})
+
+	return slices.CompactFunc(dates, func(a, b Date) bool {
+		return a.Equal(b)
+	})
+}
+
 type Daily struct {
 	Start Date
 }
@@ -73,7 +301,9 @@ func (d Daily) RecursOn(date Date) bool {
 	return date.Equal(d.Start) || date.After(d.Start)
 }
 
-func (d Daily) First() Date { return FirstRecurAfter(d, d.Start.Add(-1)) }
+func (d Daily) First() Date { return d.Next(d.Start.Add(-1)) }
+
+func (d Daily) Next(after Date) Date { return from(d.Start, after) }
 
 func (d Daily) String() string {
 	return fmt.Sprintf("%s, daily", d.Start.String())
@@ -110,21 +340,18 @@ func (nd EveryNDays) RecursOn(date Date) bool {
 	if nd.Start.After(date) {
 		return false
 	}
-
-	testDate := nd.Start
-	for {
-		switch {
-		case testDate.Equal(date):
-			return true
-		case testDate.After(date):
-			return false
-		default:
-			testDate = testDate.Add(nd.N)
-		}
+	if nd.N < 1 {
+		return nd.Start.Equal(date)
 	}
+
+	return nd.Start.DaysBetween(date)%nd.N == 0
 }
 
-func (nd EveryNDays) First() Date { return FirstRecurAfter(nd, nd.Start.Add(-1)) }
+func (nd EveryNDays) First() Date { return nd.Next(nd.Start.Add(-1)) }
+
+func (nd EveryNDays) Next(after Date) Date {
+	return nextInterval(nd.Start, after, nd.N)
+}
 
 func (nd EveryNDays) String() string {
 	return fmt.Sprintf("%s, every %d days", nd.Start.String(), nd.N)
@@ -177,7 +404,18 @@ func (w Weekly) RecursOn(date Date) bool {
 	return false
 }
 
-func (w Weekly) First() Date { return FirstRecurAfter(w, w.Start.Add(-1)) }
+func (w Weekly) First() Date { return w.Next(w.Start.Add(-1)) }
+
+func (w Weekly) Next(after Date) Date {
+	d := from(w.Start, after)
+	for i := 0; i < 7; i++ {
+		if w.RecursOn(d.Add(i)) {
+			return d.Add(i)
+		}
+	}
+
+	return Date{}
+}
 
 func (w Weekly) String() string {
 	weekdayStrs := []string{}
@@ -227,7 +465,11 @@ func (enw EveryNWeeks) RecursOn(date Date) bool {
 	return enw.Start.DaysBetween(date)%intervalDays == 0
 }
 
-func (enw EveryNWeeks) First() Date { return FirstRecurAfter(enw, enw.Start.Add(-1)) }
+func (enw EveryNWeeks) First() Date { return enw.Next(enw.Start.Add(-1)) }
+
+func (enw EveryNWeeks) Next(after Date) Date {
+	return nextInterval(enw.Start, after, enw.N*7)
+}
 
 func (enw EveryNWeeks) String() string {
 	return fmt.Sprintf("%s, every %d weeks", enw.Start.String(), enw.N)
@@ -263,22 +505,302 @@ func (enm EveryNMonths) RecursOn(date Date) bool {
 	if enm.Start.After(date) {
 		return false
 	}
+	if enm.N < 1 {
+		return enm.Start.Equal(date)
+	}
 
-	tDate := enm.Start
-	for {
-		if tDate.Equal(date) {
+	// a start day that does not exist in every month, like the 31st,
+	// overflows into the next month
+	months := enm.Start.MonthsBetween(date)
+	for _, m := range []int{months - 1, months} {
+		if m >= 0 && m%enm.N == 0 && enm.Start.AddMonths(m).Equal(date) {
 			return true
 		}
-		if tDate.After(date) {
-			return false
-		}
-		tDate = tDate.AddMonths(enm.N)
 	}
 
+	return false
 }
 
-func (enm EveryNMonths) First() Date { return FirstRecurAfter(enm, enm.Start.Add(-1)) }
+func (enm EveryNMonths) First() Date { return enm.Next(enm.Start.Add(-1)) }
+
+func (enm EveryNMonths) Next(after Date) Date {
+	if enm.Start.After(after) {
+		return enm.Start
+	}
+	if enm.N < 1 {
+		return Date{}
+	}
+
+	// start one period early, for the overflow described in RecursOn
+	m := max(0, (enm.Start.MonthsBetween(after)-1)/enm.N*enm.N)
+	for {
+		if d := enm.Start.AddMonths(m); d.After(after) {
+			return d
+		}
+		m += enm.N
+	}
+}
 
 func (enm EveryNMonths) String() string {
 	return fmt.Sprintf("%s, every %d months", enm.Start.String(), enm.N)
 }
+
+type MonthlyWeekday struct {
+	Start   Date
+	Nth     int // 1 to 5, or -1 for the last one
+	Weekday time.Weekday
+}
+
+// yyyy-mm-dd, monthly, 2nd tuesday
+// yyyy-mm-dd, monthly, last friday
+func ParseMonthlyWeekday(start Date, terms []string) (Recurrer, bool) {
+	if len(terms) != 2 || terms[0] != "monthly" {
+		return nil, false
+	}
+
+	nthStr, wdStr, ok := strings.Cut(terms[1], " ")
+	if !ok {
+		return nil, false
+	}
+	nth, ok := parseOrdinal(nthStr)
+	if !ok {
+		return nil, false
+	}
+	wd, ok := ParseWeekday(wdStr)
+	if !ok {
+		return nil, false
+	}
+
+	return MonthlyWeekday{
+		Start:   start,
+		Nth:     nth,
+		Weekday: wd,
+	}, true
+}
+
+func (mw MonthlyWeekday) RecursOn(date Date) bool {
+	if mw.Start.After(date) {
+		return false
+	}
+
+	return mw.inMonth(date.Time().Year(), date.Time().Month()).Equal(date)
+}
+
+func (mw MonthlyWeekday) First() Date { return mw.Next(mw.Start.Add(-1)) }
+
+func (mw MonthlyWeekday) Next(after Date) Date {
+	d := from(mw.Start, after)
+	// not every month has a fifth weekday, but there is one within a year
+	for i := 0; i < 13; i++ {
+		month := NewDate(d.Time().Year(), int(d.Time().Month()), 1).AddMonths(i)
+		cand := mw.inMonth(month.Time().Year(), month.Time().Month())
+		if !cand.IsZero() && !d.After(cand) {
+			return cand
+		}
+	}
+
+	return Date{}
+}
+
+// inMonth returns the date of the recurrence in the given month, or a zero
+// date if the month does not have it
+func (mw MonthlyWeekday) inMonth(year int, month time.Month) Date {
+	last := daysIn(year, month)
+	if mw.Nth == -1 {
+		lastWd := NewDate(year, int(month), last).Weekday()
+		return NewDate(year, int(month), last-(int(lastWd)-int(mw.Weekday)+7)%7)
+	}
+
+	firstWd := NewDate(year, int(month), 1).Weekday()
+	day := 1 + (int(mw.Weekday)-int(firstWd)+7)%7 + (mw.Nth-1)*7
+	if mw.Nth < 1 || day > last {
+		return Date{}
+	}
+
+	return NewDate(year, int(month), day)
+}
+
+func (mw MonthlyWeekday) String() string {
+	return fmt.Sprintf("%s, monthly, %s %s", mw.Start.String(), ordinal(mw.Nth), strings.ToLower(mw.Weekday.String()))
+}
+
+type MonthlyLastDay struct {
+	Start Date
+}
+
+// yyyy-mm-dd, monthly, last day
+func ParseMonthlyLastDay(start Date, terms []string) (Recurrer, bool) {
+	if len(terms) != 2 || terms[0] != "monthly" || terms[1] != "last day" {
+		return nil, false
+	}
+
+	return MonthlyLastDay{
+		Start: start,
+	}, true
+}
+
+func (mld MonthlyLastDay) RecursOn(date Date) bool {
+	if mld.Start.After(date) {
+		return false
+	}
+
+	return date.Add(1).Day() == 1
+}
+
+func (mld MonthlyLastDay) First() Date { return mld.Next(mld.Start.Add(-1)) }
+
+func (mld MonthlyLastDay) Next(after Date) Date {
+	d := from(mld.Start, after)
+	year, month, _ := d.Time().Date()
+
+	return NewDate(year, int(month), daysIn(year, month))
+}
+
+func (mld MonthlyLastDay) String() string {
+	return fmt.Sprintf("%s, monthly, last day", mld.Start.String())
+}
+
+type Yearly struct {
+	Start Date
+	Month time.Month
+	Day   int
+}
+
+// yyyy-mm-dd, yearly, 03-15
+func ParseYearly(start Date, terms []string) (Recurrer, bool) {
+	if len(terms) != 2 || terms[0] != "yearly" {
+		return nil, false
+	}
+
+	// parse with a leap year, so that 02-29 is accepted
+	md, err := time.Parse("2006-01-02", fmt.Sprintf("2024-%s", terms[1]))
+	if err != nil {
+		return nil, false
+	}
+
+	return Yearly{
+		Start: start,
+		Month: md.Month(),
+		Day:   md.Day(),
+	}, true
+}
+
+func (y Yearly) RecursOn(date Date) bool {
+	if y.Start.After(date) {
+		return false
+	}
+
+	return date.Time().Month() == y.Month && date.Day() == y.Day
+}
+
+func (y Yearly) First() Date { return y.Next(y.Start.Add(-1)) }
+
+func (y Yearly) Next(after Date) Date {
+	d := from(y.Start, after)
+	// the 29th of february can take up to eight years
+	for year := d.Time().Year(); year <= d.Time().Year()+8; year++ {
+		if y.Day > daysIn(year, y.Month) {
+			continue
+		}
+		if cand := NewDate(year, int(y.Month), y.Day); !d.After(cand) {
+			return cand
+		}
+	}
+
+	return Date{}
+}
+
+func (y Yearly) String() string {
+	return fmt.Sprintf("%s, yearly, %02d-%02d", y.Start.String(), y.Month, y.Day)
+}
+
+type EveryWeekday struct {
+	Start Date
+}
+
+// yyyy-mm-dd, every weekday
+func ParseEveryWeekday(start Date, terms []string) (Recurrer, bool) {
+	if len(terms) != 1 || terms[0] != "every weekday" {
+		return nil, false
+	}
+
+	return EveryWeekday{
+		Start: start,
+	}, true
+}
+
+func (ew EveryWeekday) RecursOn(date Date) bool {
+	if ew.Start.After(date) {
+		return false
+	}
+
+	wd := date.Weekday()
+	return wd != time.Saturday && wd != time.Sunday
+}
+
+func (ew EveryWeekday) First() Date { return ew.Next(ew.Start.Add(-1)) }
+
+func (ew EveryWeekday) Next(after Date) Date {
+	d := from(ew.Start, after)
+	for !ew.RecursOn(d) {
+		d = d.Add(1)
+	}
+
+	return d
+}
+
+func (ew EveryWeekday) String() string {
+	return fmt.Sprintf("%s, every weekday", ew.Start.String())
+}
+
+func parseOrdinal(s string) (int, bool) {
+	switch s {
+	case "1st", "first":
+		return 1, true
+	case "2nd", "second":
+		return 2, true
+	case "3rd", "third":
+		return 3, true
+	case "4th", "fourth":
+		return 4, true
+	case "5th", "fifth":
+		return 5, true
+	case "last":
+		return -1, true
+	default:
+		return 0, false
+	}
+}
+
+func ordinal(n int) string {
+	switch n {
+	case -1:
+		return "last"
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	case 3:
+		return "3rd"
+	default:
+		return fmt.Sprintf("%dth", n)
+	}
+}
+
+// nextInterval returns the first date after the given one in a series that
+// repeats every n days from start
+func nextInterval(start, after Date, n int) Date {
+	if start.After(after) {
+		return start
+	}
+	if n < 1 {
+		return Date{}
+	}
+
+	return start.Add((start.DaysBetween(after)/n + 1) * n)
+}
+
+// daysIn returns the number of days in a month
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
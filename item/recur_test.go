@@ -1,6 +1,7 @@
 package item_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -426,3 +427,605 @@ func TestEveryNMonths(t *testing.T) {
 		}
 	})
 }
+
+func TestEnding(t *testing.T) {
+	t.Parallel()
+
+	weekly := item.Weekly{
+		Start:    item.NewDate(2024, 1, 1), // a monday
+		Weekdays: item.Weekdays{time.Monday},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		recurStr  string
+		exp       item.Recurrer
+		expFirst  item.Date
+		expLast   item.Date
+		expRecurs map[item.Date]bool
+	}{
+		{
+			name:     "no base",
+			recurStr: "2024-01-01, until 2024-06-30",
+		},
+		{
+			name:     "invalid date",
+			recurStr: "2024-01-01, daily, until someday",
+		},
+		{
+			name:     "no times",
+			recurStr: "2024-01-01, daily, 0 times",
+		},
+		{
+			name:     "too many times",
+			recurStr: "2024-01-01, daily, 20000000 times",
+		},
+		{
+			name:     "until",
+			recurStr: "2024-01-01, weekly, monday, until 2024-06-30",
+			exp:      item.NewEnding(weekly, item.NewDate(2024, 6, 30), 0),
+			expFirst: item.NewDate(2024, 1, 1),
+			expLast:  item.NewDate(2024, 6, 30),
+			expRecurs: map[item.Date]bool{
+				item.NewDate(2024, 1, 1):  true,
+				item.NewDate(2024, 6, 24): true,
+				item.NewDate(2024, 7, 1):  false,
+			},
+		},
+		{
+			name:     "times",
+			recurStr: "2024-01-01, weekly, monday, 10 times",
+			exp:      item.NewEnding(weekly, item.Date{}, 10),
+			expFirst: item.NewDate(2024, 1, 1),
+			expLast:  item.NewDate(2024, 3, 4),
+			expRecurs: map[item.Date]bool{
+				item.NewDate(2024, 1, 1):  true,
+				item.NewDate(2024, 3, 4):  true,
+				item.NewDate(2024, 3, 11): false,
+			},
+		},
+		{
+			name:     "until before start",
+			recurStr: "2024-01-01, daily, until 2023-12-31",
+			exp:      item.NewEnding(item.Daily{Start: item.NewDate(2024, 1, 1)}, item.NewDate(2023, 12, 31), 0),
+			expLast:  item.NewDate(2023, 12, 31),
+			expRecurs: map[item.Date]bool{
+				item.NewDate(2024, 1, 1): false,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			act := item.NewRecurrer(tc.recurStr)
+			if diff := cmp.Diff(tc.exp, act); diff != "" {
+				t.Errorf("(-exp +got):\n%s", diff)
+			}
+			if tc.exp == nil {
+				return
+			}
+			if act.String() != tc.recurStr {
+				t.Errorf("exp %v, got %v", tc.recurStr, act.String())
+			}
+			if !tc.expFirst.Equal(act.First()) {
+				t.Errorf("exp %v, got %v", tc.expFirst, act.First())
+			}
+			actLast := act.(item.Ending).Last()
+			if !tc.expLast.Equal(actLast) {
+				t.Errorf("exp %v, got %v", tc.expLast, actLast)
+			}
+			if next := act.Next(tc.expLast); !next.IsZero() {
+				t.Errorf("exp zero date, got %v", next)
+			}
+			for d, exp := range tc.expRecurs {
+				if exp != act.RecursOn(d) {
+					t.Errorf("exp %v on %v, got %v", exp, d, act.RecursOn(d))
+				}
+			}
+		})
+	}
+}
+
+func TestMonthlyWeekday(t *testing.T) {
+	t.Parallel()
+
+	start := item.NewDate(2024, 1, 1)
+
+	t.Run("parse", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			recurStr string
+			exp      item.Recurrer
+		}{
+			{
+				name:     "no ordinal",
+				recurStr: "2024-01-01, monthly, tuesday",
+			},
+			{
+				name:     "unknown ordinal",
+				recurStr: "2024-01-01, monthly, 6th tuesday",
+			},
+			{
+				name:     "unknown weekday",
+				recurStr: "2024-01-01, monthly, 2nd festivus",
+			},
+			{
+				name:     "nth",
+				recurStr: "2024-01-01, monthly, 2nd tuesday",
+				exp:      item.MonthlyWeekday{Start: start, Nth: 2, Weekday: time.Tuesday},
+			},
+			{
+				name:     "words",
+				recurStr: "2024-01-01, monthly, third wed",
+				exp:      item.MonthlyWeekday{Start: start, Nth: 3, Weekday: time.Wednesday},
+			},
+			{
+				name:     "last",
+				recurStr: "2024-01-01, monthly, last friday",
+				exp:      item.MonthlyWeekday{Start: start, Nth: -1, Weekday: time.Friday},
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				if diff := cmp.Diff(tc.exp, item.NewRecurrer(tc.recurStr)); diff != "" {
+					t.Errorf("(-exp +got):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("string", func(t *testing.T) {
+		for _, tc := range []struct {
+			mw  item.MonthlyWeekday
+			exp string
+		}{
+			{item.MonthlyWeekday{Start: start, Nth: 1, Weekday: time.Monday}, "2024-01-01, monthly, 1st monday"},
+			{item.MonthlyWeekday{Start: start, Nth: 2, Weekday: time.Tuesday}, "2024-01-01, monthly, 2nd tuesday"},
+			{item.MonthlyWeekday{Start: start, Nth: 3, Weekday: time.Wednesday}, "2024-01-01, monthly, 3rd wednesday"},
+			{item.MonthlyWeekday{Start: start, Nth: 4, Weekday: time.Thursday}, "2024-01-01, monthly, 4th thursday"},
+			{item.MonthlyWeekday{Start: start, Nth: 5, Weekday: time.Friday}, "2024-01-01, monthly, 5th friday"},
+			{item.MonthlyWeekday{Start: start, Nth: -1, Weekday: time.Sunday}, "2024-01-01, monthly, last sunday"},
+		} {
+			if tc.exp != tc.mw.String() {
+				t.Errorf("exp %v, got %v", tc.exp, tc.mw.String())
+			}
+			if diff := cmp.Diff(tc.mw, item.NewRecurrer(tc.exp)); diff != "" {
+				t.Errorf("(-exp +got):\n%s", diff)
+			}
+		}
+	})
+
+	t.Run("recurs_on", func(t *testing.T) {
+		secondTue := item.MonthlyWeekday{Start: start, Nth: 2, Weekday: time.Tuesday}
+		lastFri := item.MonthlyWeekday{Start: start, Nth: -1, Weekday: time.Friday}
+		fifthFri := item.MonthlyWeekday{Start: start, Nth: 5, Weekday: time.Friday}
+		for _, tc := range []struct {
+			name string
+			mw   item.MonthlyWeekday
+			date item.Date
+			exp  bool
+		}{
+			{name: "before start", mw: secondTue, date: item.NewDate(2023, 12, 12)},
+			{name: "first tuesday", mw: secondTue, date: item.NewDate(2024, 1, 2)},
+			{name: "second tuesday", mw: secondTue, date: item.NewDate(2024, 1, 9), exp: true},
+			{name: "second tuesday next month", mw: secondTue, date: item.NewDate(2024, 2, 13), exp: true},
+			{name: "wrong weekday", mw: secondTue, date: item.NewDate(2024, 1, 10)},
+			{name: "last friday", mw: lastFri, date: item.NewDate(2024, 1, 26), exp: true},
+			{name: "last friday of five", mw: lastFri, date: item.NewDate(2024, 3, 29), exp: true},
+			{name: "fourth friday of five", mw: lastFri, date: item.NewDate(2024, 3, 22)},
+			{name: "fifth friday", mw: fifthFri, date: item.NewDate(2024, 3, 29), exp: true},
+			{name: "no fifth friday", mw: fifthFri, date: item.NewDate(2024, 2, 23)},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				if tc.exp != tc.mw.RecursOn(tc.date) {
+					t.Errorf("exp %v, got %v", tc.exp, tc.mw.RecursOn(tc.date))
+				}
+			})
+		}
+	})
+
+	t.Run("first", func(t *testing.T) {
+		mw := item.MonthlyWeekday{Start: item.NewDate(2024, 1, 10), Nth: 2, Weekday: time.Tuesday}
+		exp := item.NewDate(2024, 2, 13)
+		if !exp.Equal(mw.First()) {
+			t.Errorf("exp %v, got %v", exp, mw.First())
+		}
+	})
+}
+
+func TestMonthlyLastDay(t *testing.T) {
+	t.Parallel()
+
+	lastDay := item.MonthlyLastDay{
+		Start: item.NewDate(2023, 1, 1),
+	}
+	lastDayStr := "2023-01-01, monthly, last day"
+
+	t.Run("parse", func(t *testing.T) {
+		if diff := cmp.Diff(lastDay, item.NewRecurrer(lastDayStr)); diff != "" {
+			t.Errorf("(-exp +got):\n%s", diff)
+		}
+		if rec := item.NewRecurrer("2023-01-01, monthly, last week"); rec != nil {
+			t.Errorf("exp nil, got %v", rec)
+		}
+	})
+
+	t.Run("string", func(t *testing.T) {
+		if lastDayStr != lastDay.String() {
+			t.Errorf("exp %v, got %v", lastDayStr, lastDay.String())
+		}
+	})
+
+	t.Run("recurs_on", func(t *testing.T) {
+		for _, tc := range []struct {
+			name string
+			date item.Date
+			exp  bool
+		}{
+			{name: "before start", date: item.NewDate(2022, 12, 31)},
+			{name: "31 days", date: item.NewDate(2023, 1, 31), exp: true},
+			{name: "30 days", date: item.NewDate(2023, 4, 30), exp: true},
+			{name: "february", date: item.NewDate(2023, 2, 28), exp: true},
+			{name: "leap year", date: item.NewDate(2024, 2, 29), exp: true},
+			{name: "not last in leap year", date: item.NewDate(2024, 2, 28)},
+			{name: "end of year", date: item.NewDate(2023, 12, 31), exp: true},
+			{name: "middle", date: item.NewDate(2023, 3, 15)},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				if tc.exp != lastDay.RecursOn(tc.date) {
+					t.Errorf("exp %v, got %v", tc.exp, lastDay.RecursOn(tc.date))
+				}
+			})
+		}
+	})
+}
+
+func TestYearly(t *testing.T) {
+	t.Parallel()
+
+	start := item.NewDate(2024, 1, 1)
+
+	t.Run("parse", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			recurStr string
+			exp      item.Recurrer
+		}{
+			{
+				name:     "no date",
+				recurStr: "2024-01-01, yearly",
+			},
+			{
+				name:     "invalid month",
+				recurStr: "2024-01-01, yearly, 13-01",
+			},
+			{
+				name:     "invalid day",
+				recurStr: "2024-01-01, yearly, 02-30",
+			},
+			{
+				name:     "valid",
+				recurStr: "2024-01-01, yearly, 03-15",
+				exp:      item.Yearly{Start: start, Month: time.March, Day: 15},
+			},
+			{
+				name:     "leap day",
+				recurStr: "2024-01-01, yearly, 02-29",
+				exp:      item.Yearly{Start: start, Month: time.February, Day: 29},
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				if diff := cmp.Diff(tc.exp, item.NewRecurrer(tc.recurStr)); diff != "" {
+					t.Errorf("(-exp +got):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("string", func(t *testing.T) {
+		yearly := item.Yearly{Start: start, Month: time.March, Day: 5}
+		exp := "2024-01-01, yearly, 03-05"
+		if exp != yearly.String() {
+			t.Errorf("exp %v, got %v", exp, yearly.String())
+		}
+	})
+
+	t.Run("recurs_on", func(t *testing.T) {
+		march := item.Yearly{Start: start, Month: time.March, Day: 15}
+		leap := item.Yearly{Start: start, Month: time.February, Day: 29}
+		for _, tc := range []struct {
+			name   string
+			yearly item.Yearly
+			date   item.Date
+			exp    bool
+		}{
+			{name: "before start", yearly: march, date: item.NewDate(2023, 3, 15)},
+			{name: "on", yearly: march, date: item.NewDate(2024, 3, 15), exp: true},
+			{name: "next year", yearly: march, date: item.NewDate(2025, 3, 15), exp: true},
+			{name: "other day", yearly: march, date: item.NewDate(2024, 3, 16)},
+			{name: "other month", yearly: march, date: item.NewDate(2024, 4, 15)},
+			{name: "leap year", yearly: leap, date: item.NewDate(2028, 2, 29), exp: true},
+			{name: "no leap year", yearly: leap, date: item.NewDate(2025, 2, 28)},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				if tc.exp != tc.yearly.RecursOn(tc.date) {
+					t.Errorf("exp %v, got %v", tc.exp, tc.yearly.RecursOn(tc.date))
+				}
+			})
+		}
+	})
+
+	t.Run("first", func(t *testing.T) {
+		yearly := item.Yearly{Start: item.NewDate(2024, 3, 16), Month: time.March, Day: 15}
+		exp := item.NewDate(2025, 3, 15)
+		if !exp.Equal(yearly.First()) {
+			t.Errorf("exp %v, got %v", exp, yearly.First())
+		}
+	})
+}
+
+func TestEveryWeekday(t *testing.T) {
+	t.Parallel()
+
+	every := item.EveryWeekday{
+		Start: item.NewDate(2024, 1, 3), // a wednesday
+	}
+	everyStr := "2024-01-03, every weekday"
+
+	t.Run("parse", func(t *testing.T) {
+		if diff := cmp.Diff(every, item.NewRecurrer(everyStr)); diff != "" {
+			t.Errorf("(-exp +got):\n%s", diff)
+		}
+	})
+
+	t.Run("string", func(t *testing.T) {
+		if everyStr != every.String() {
+			t.Errorf("exp %v, got %v", everyStr, every.String())
+		}
+	})
+
+	t.Run("recurs_on", func(t *testing.T) {
+		for _, tc := range []struct {
+			name string
+			date item.Date
+			exp  bool
+		}{
+			{name: "before start", date: item.NewDate(2024, 1, 2)},
+			{name: "on start", date: item.NewDate(2024, 1, 3), exp: true},
+			{name: "friday", date: item.NewDate(2024, 1, 5), exp: true},
+			{name: "saturday", date: item.NewDate(2024, 1, 6)},
+			{name: "sunday", date: item.NewDate(2024, 1, 7)},
+			{name: "monday", date: item.NewDate(2024, 1, 8), exp: true},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				if tc.exp != every.RecursOn(tc.date) {
+					t.Errorf("exp %v, got %v", tc.exp, every.RecursOn(tc.date))
+				}
+			})
+		}
+	})
+
+	t.Run("first", func(t *testing.T) {
+		weekend := item.EveryWeekday{Start: item.NewDate(2024, 1, 6)}
+		exp := item.NewDate(2024, 1, 8)
+		if !exp.Equal(weekend.First()) {
+			t.Errorf("exp %v, got %v", exp, weekend.First())
+		}
+	})
+}
+
+func TestExcept(t *testing.T) {
+	t.Parallel()
+
+	weekly := item.Weekly{
+		Start:    item.NewDate(2024, 12, 2), // a monday
+		Weekdays: item.Weekdays{time.Monday},
+	}
+	except := item.Except{
+		Recurrer: weekly,
+		Dates:    []item.Date{item.NewDate(2024, 12, 23), item.NewDate(2024, 12, 30)},
+	}
+	exceptStr := "2024-12-02, weekly, monday, except 2024-12-23 & 2024-12-30"
+
+	t.Run("parse", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			recurStr string
+			exp      item.Recurrer
+		}{
+			{
+				name:     "no base",
+				recurStr: "2024-12-02, except 2024-12-23",
+			},
+			{
+				name:     "invalid date",
+				recurStr: "2024-12-02, daily, except someday",
+			},
+			{
+				name:     "unordered",
+				recurStr: "2024-12-02, weekly, monday, except 2024-12-30 & 2024-12-23 & 2024-12-30",
+				exp:      except,
+			},
+			{
+				name:     "with ending",
+				recurStr: "2024-12-02, weekly, monday, 5 times, except 2024-12-23",
+				exp: item.Except{
+					Recurrer: item.NewEnding(weekly, item.Date{}, 5),
+					Dates:    []item.Date{item.NewDate(2024, 12, 23)},
+				},
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				if diff := cmp.Diff(tc.exp, item.NewRecurrer(tc.recurStr)); diff != "" {
+					t.Errorf("(-exp +got):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("string", func(t *testing.T) {
+		if exceptStr != except.String() {
+			t.Errorf("exp %v, got %v", exceptStr, except.String())
+		}
+	})
+
+	t.Run("recurs_on", func(t *testing.T) {
+		for _, tc := range []struct {
+			name string
+			date item.Date
+			exp  bool
+		}{
+			{name: "normal", date: item.NewDate(2024, 12, 16), exp: true},
+			{name: "exception", date: item.NewDate(2024, 12, 23)},
+			{name: "not recurring", date: item.NewDate(2024, 12, 24)},
+			{name: "after exceptions", date: item.NewDate(2025, 1, 6), exp: true},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				if tc.exp != except.RecursOn(tc.date) {
+					t.Errorf("exp %v, got %v", tc.exp, except.RecursOn(tc.date))
+				}
+			})
+		}
+	})
+
+	t.Run("first recur after", func(t *testing.T) {
+		exp := item.NewDate(2025, 1, 6)
+		if act := except.Next(item.NewDate(2024, 12, 16)); !exp.Equal(act) {
+			t.Errorf("exp %v, got %v", exp, act)
+		}
+		first := item.Except{Recurrer: weekly, Dates: []item.Date{weekly.Start}}
+		exp = item.NewDate(2024, 12, 9)
+		if !exp.Equal(first.First()) {
+			t.Errorf("exp %v, got %v", exp, first.First())
+		}
+	})
+
+	t.Run("with exception", func(t *testing.T) {
+		act := item.WithException(weekly, item.NewDate(2024, 12, 30))
+		act = item.WithException(act, item.NewDate(2024, 12, 23))
+		if diff := cmp.Diff(except, act); diff != "" {
+			t.Errorf("(-exp +got):\n%s", diff)
+		}
+	})
+}
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	start := item.NewDate(2024, 1, 31)
+	for _, tc := range []struct {
+		name     string
+		recurrer item.Recurrer
+	}{
+		{name: "daily", recurrer: item.Daily{Start: start}},
+		{name: "every n days", recurrer: item.EveryNDays{Start: start, N: 9}},
+		{name: "weekly", recurrer: item.Weekly{Start: start, Weekdays: item.Weekdays{time.Tuesday, time.Saturday}}},
+		{name: "every n weeks", recurrer: item.EveryNWeeks{Start: start, N: 3}},
+		{name: "every month", recurrer: item.EveryNMonths{Start: start, N: 1}},
+		{name: "every n months", recurrer: item.EveryNMonths{Start: start, N: 5}},
+		{name: "monthly weekday", recurrer: item.MonthlyWeekday{Start: start, Nth: 2, Weekday: time.Tuesday}},
+		{name: "monthly fifth weekday", recurrer: item.MonthlyWeekday{Start: start, Nth: 5, Weekday: time.Sunday}},
+		{name: "monthly last weekday", recurrer: item.MonthlyWeekday{Start: start, Nth: -1, Weekday: time.Friday}},
+		{name: "monthly last day", recurrer: item.MonthlyLastDay{Start: start}},
+		{name: "yearly", recurrer: item.Yearly{Start: start, Month: time.March, Day: 15}},
+		{name: "leap day", recurrer: item.Yearly{Start: start, Month: time.February, Day: 29}},
+		{name: "every weekday", recurrer: item.EveryWeekday{Start: start}},
+		{name: "ending", recurrer: item.NewEnding(item.Daily{Start: start}, item.Date{}, 40)},
+		{name: "except", recurrer: item.Except{Recurrer: item.Daily{Start: start}, Dates: []item.Date{item.NewDate(2024, 2, 2)}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// walk back and compare with checking every single day
+			end := item.NewDate(2029, 12, 31)
+			exp := tc.recurrer.Next(end)
+			if !tc.recurrer.RecursOn(exp) && !exp.IsZero() {
+				t.Errorf("exp %v to recur", exp)
+			}
+			for d := end; !start.After(d.Add(1)); d = d.Add(-1) {
+				if act := tc.recurrer.Next(d); !exp.Equal(act) {
+					t.Fatalf("after %v: exp %v, got %v", d, exp, act)
+				}
+				if tc.recurrer.RecursOn(d) {
+					exp = d
+				}
+			}
+			if !exp.Equal(tc.recurrer.First()) {
+				t.Errorf("exp %v, got %v", exp, tc.recurrer.First())
+			}
+		})
+	}
+
+	t.Run("never", func(t *testing.T) {
+		never := item.Weekly{Start: start}
+		if act := never.Next(start); !act.IsZero() {
+			t.Errorf("exp zero date, got %v", act)
+		}
+	})
+}
+
+func BenchmarkRecursOn(b *testing.B) {
+	start := item.NewDate(1900, 1, 1)
+	for _, dist := range []int{1, 100, 1000} {
+		date := item.NewDate(1900+dist, 6, 1)
+		for _, bc := range []struct {
+			name     string
+			recurrer item.Recurrer
+		}{
+			{name: "every n days", recurrer: item.EveryNDays{Start: start, N: 3}},
+			{name: "every n weeks", recurrer: item.EveryNWeeks{Start: start, N: 3}},
+			{name: "every n months", recurrer: item.EveryNMonths{Start: start, N: 3}},
+			{name: "ending count", recurrer: item.NewEnding(item.EveryNDays{Start: start, N: 3}, item.Date{}, item.MaxCount)},
+			{name: "ending until", recurrer: item.NewEnding(item.EveryNDays{Start: start, N: 3}, item.NewDate(3000, 1, 1), 0)},
+		} {
+			b.Run(fmt.Sprintf("%s/%d years", bc.name, dist), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					bc.recurrer.RecursOn(date)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkNext(b *testing.B) {
+	start := item.NewDate(1900, 1, 1)
+	for _, dist := range []int{1, 100, 1000} {
+		after := item.NewDate(1900+dist, 6, 1)
+		for _, bc := range []struct {
+			name     string
+			recurrer item.Recurrer
+		}{
+			{name: "every n days", recurrer: item.EveryNDays{Start: start, N: 3}},
+			{name: "every n months", recurrer: item.EveryNMonths{Start: start, N: 3}},
+			{name: "monthly weekday", recurrer: item.MonthlyWeekday{Start: start, Nth: 5, Weekday: time.Monday}},
+			{name: "yearly", recurrer: item.Yearly{Start: start, Month: time.February, Day: 29}},
+			{name: "never", recurrer: item.Weekly{Start: start}},
+			{name: "ending count", recurrer: item.NewEnding(item.EveryNDays{Start: start, N: 3}, item.Date{}, item.MaxCount)},
+		} {
+			b.Run(fmt.Sprintf("%s/%d years", bc.name, dist), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					bc.recurrer.Next(after)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkNewEnding(b *testing.B) {
+	start := item.NewDate(1900, 1, 1)
+	for _, bc := range []struct {
+		name     string
+		recurrer item.Recurrer
+	}{
+		{name: "every n days", recurrer: item.EveryNDays{Start: start, N: 3}},
+		{name: "weekly", recurrer: item.Weekly{Start: start, Weekdays: item.Weekdays{time.Monday, time.Thursday}}},
+		{name: "monthly weekday", recurrer: item.MonthlyWeekday{Start: start, Nth: 5, Weekday: time.Monday}},
+	} {
+		b.Run(fmt.Sprintf("%s/max count", bc.name), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				item.NewEnding(bc.recurrer, item.Date{}, item.MaxCount)
+			}
+		})
+	}
+	b.Run("parse max count", func(b *testing.B) {
+		recurStr := fmt.Sprintf("1900-01-01, daily, %d times", item.MaxCount)
+		for i := 0; i < b.N; i++ {
+			item.NewRecurrer(recurStr)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package item
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseRecurrer parses a recurrer as it is typed in by a user, with ref as
+// the date that "today" refers to. That is either the form that NewRecurrer
+// understands, or a phrase like:
+//
+//   - daily, every day, every 3 days, every weekday
+//   - weekly, every 2 weeks, every monday and thursday
+//   - monthly, every 3 months, monthly on the 15th, monthly on the last day,
+//     monthly on the 2nd tuesday. A day that a month does not have, like
+//     the 31st, is skipped at the start and overflows after that.
+//   - yearly, every year on dec 24
+//
+// A phrase can be followed by "from <date>" to set the start, which is ref
+// otherwise, and after that by "until <date>" or "<n> times", with n at most
+// MaxCount. Dates are parsed by ParseDate. The resulting recurrer still has
+// the canonical form as String(), so that is what gets stored.
+func ParseRecurrer(recurStr string, ref Date) Recurrer {
+	if rec := newRecurrer(recurStr, ref); rec != nil {
+		return rec
+	}
+
+	phrase := strings.Join(strings.Fields(strings.ToLower(recurStr)), " ")
+	var end Ending
+	if phr, untilStr, ok := strings.Cut(phrase, " until "); ok {
+		end.Until = ParseDate(untilStr, ref)
+		if end.Until.IsZero() {
+			return nil
+		}
+		phrase = phr
+	} else if phr, ok := strings.CutSuffix(phrase, " times"); ok {
+		i := strings.LastIndex(phr, " ")
+		if i < 0 {
+			return nil
+		}
+		count, err := strconv.Atoi(phr[i+1:])
+		if err != nil || count < 1 || count > MaxCount {
+			return nil
+		}
+		end.Count = count
+		phrase = phr[:i]
+	}
+
+	start := ref
+	for _, sep := range []string{" from ", " starting "} {
+		if phr, startStr, ok := strings.Cut(phrase, sep); ok {
+			start = ParseDate(startStr, ref)
+			if start.IsZero() {
+				return nil
+			}
+			phrase = phr
+			break
+		}
+	}
+
+	rec := parsePhrase(start, phrase)
+	if rec == nil {
+		return nil
+	}
+	if !end.Until.IsZero() || end.Count > 0 {
+		return NewEnding(rec, end.Until, end.Count)
+	}
+
+	return rec
+}
+
+func parsePhrase(start Date, phrase string) Recurrer {
+	pattern, on, _ := strings.Cut(phrase, " on ")
+	on = strings.TrimPrefix(on, "the ")
+	terms := strings.Fields(pattern)
+
+	var unit string
+	n := 1
+	switch {
+	case len(terms) == 1:
+		unit = terms[0]
+	case len(terms) == 2 && terms[0] == "every":
+		unit = terms[1]
+	case len(terms) == 3 && terms[0] == "every" && isNumber(terms[1]):
+		n, _ = strconv.Atoi(terms[1])
+		if n < 1 {
+			return nil
+		}
+		unit = terms[2]
+	case len(terms) > 2 && terms[0] == "every":
+		return parseWeekdayList(start, terms[1:])
+	default:
+		return nil
+	}
+
+	switch unit {
+	case "daily", "day", "days":
+		switch {
+		case on != "":
+			return nil
+		case n == 1:
+			return Daily{Start: start}
+		default:
+			return EveryNDays{Start: start, N: n}
+		}
+	case "weekday", "weekdays":
+		if on != "" || n != 1 {
+			return nil
+		}
+		return EveryWeekday{Start: start}
+	case "weekly", "week", "weeks":
+		switch {
+		case on != "":
+			return nil
+		case n == 1:
+			return Weekly{Start: start, Weekdays: Weekdays{start.Weekday()}}
+		default:
+			return EveryNWeeks{Start: start, N: n}
+		}
+	case "monthly", "month", "months":
+		return parseMonthlyPhrase(start, n, on)
+	case "yearly", "annually", "year":
+		if n != 1 {
+			return nil
+		}
+		if on == "" {
+			return Yearly{Start: start, Month: start.Time().Month(), Day: start.Day()}
+		}
+		// any year will do to get the month and the day
+		md := ParseDate(on, NewDate(2024, 1, 1))
+		if md.IsZero() {
+			return nil
+		}
+		return Yearly{Start: start, Month: md.Time().Month(), Day: md.Day()}
+	}
+
+	if n != 1 || on != "" {
+		return nil
+	}
+	return parseWeekdayList(start, terms[1:])
+}
+
+// every monday and thursday, every mon, wed & fri
+func parseWeekdayList(start Date, terms []string) Recurrer {
+	wds := Weekdays{}
+	for _, term := range terms {
+		for _, wdStr := range strings.FieldsFunc(term, func(r rune) bool { return r == ',' || r == '&' }) {
+			if wdStr == "and" {
+				continue
+			}
+			wd, ok := ParseWeekday(wdStr)
+			if !ok {
+				return nil
+			}
+			wds = append(wds, wd)
+		}
+	}
+	if len(wds) == 0 {
+		return nil
+	}
+
+	return Weekly{Start: start, Weekdays: wds.Unique()}
+}
+
+// on the 15th, on the last day, on the 2nd tuesday
+//
+// A day of the month starts on the first date on or after start with that
+// day, so "monthly on the 31st" entered on february 1 starts on march 31.
+// After that it behaves like EveryNMonths, where a day that a month does not
+// have overflows into the next one. "on the last day" follows short months.
+func parseMonthlyPhrase(start Date, n int, on string) Recurrer {
+	if on == "" {
+		return EveryNMonths{Start: start, N: n}
+	}
+	if on == "last day" {
+		if n != 1 {
+			return nil
+		}
+		return MonthlyLastDay{Start: start}
+	}
+	if nthStr, wdStr, ok := strings.Cut(on, " "); ok {
+		nth, ok := parseOrdinal(nthStr)
+		if !ok || n != 1 {
+			return nil
+		}
+		wd, ok := ParseWeekday(wdStr)
+		if !ok {
+			return nil
+		}
+		return MonthlyWeekday{Start: start, Nth: nth, Weekday: wd}
+	}
+
+	day, err := strconv.Atoi(strings.TrimRight(on, "stndrh"))
+	if err != nil || day < 1 || day > 31 {
+		return nil
+	}
+	// the recurrence follows the day of the start date
+	for start.Day() != day {
+		start = start.Add(1)
+	}
+
+	return EveryNMonths{Start: start, N: n}
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
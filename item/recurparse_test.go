@@ -0,0 +1,95 @@
+package item_test
+
+import (
+	"testing"
+
+	"go-mod.ewintr.nl/planner/item"
+)
+
+func TestParseRecurrer(t *testing.T) {
+	t.Parallel()
+
+	ref := item.NewDate(2024, 1, 31) // wednesday
+	for _, tc := range []struct {
+		name  string
+		input string
+		exp   string
+	}{
+		{name: "canonical", input: "2024-02-01, daily", exp: "2024-02-01, daily"},
+		{name: "canonical relative start", input: "tomorrow, daily", exp: "2024-02-01, daily"},
+		{name: "daily", input: "daily", exp: "2024-01-31, daily"},
+		{name: "every day", input: "Every Day", exp: "2024-01-31, daily"},
+		{name: "every n days", input: "every 3 days", exp: "2024-01-31, every 3 days"},
+		{name: "every weekday", input: "every weekday", exp: "2024-01-31, every weekday"},
+		{name: "weekly", input: "weekly", exp: "2024-01-31, weekly, wednesday"},
+		{name: "every weekday name", input: "every monday", exp: "2024-01-31, weekly, monday"},
+		{name: "weekday names", input: "every monday and thursday", exp: "2024-01-31, weekly, monday & thursday"},
+		{name: "weekday list", input: "every mon, thu & fri", exp: "2024-01-31, weekly, monday & thursday & friday"},
+		{name: "every n weeks from", input: "every 2 weeks from next friday", exp: "2024-02-09, every 2 weeks"},
+		{name: "monthly", input: "monthly", exp: "2024-01-31, every 1 months"},
+		{name: "monthly on day", input: "monthly on the 15th", exp: "2024-02-15, every 1 months"},
+		{name: "monthly on day after short month", input: "monthly on the 30th", exp: "2024-03-30, every 1 months"},
+		{name: "every n months on day", input: "every 3 months on the 1st", exp: "2024-02-01, every 3 months"},
+		{name: "monthly last day", input: "monthly on the last day", exp: "2024-01-31, monthly, last day"},
+		{name: "monthly weekday", input: "every month on the 2nd tuesday", exp: "2024-01-31, monthly, 2nd tuesday"},
+		{name: "yearly", input: "every year", exp: "2024-01-31, yearly, 01-31"},
+		{name: "yearly on", input: "yearly on dec 24", exp: "2024-01-31, yearly, 12-24"},
+		{name: "until", input: "daily until dec 24", exp: "2024-01-31, daily, until 2024-12-24"},
+		{name: "times", input: "every monday 10 times", exp: "2024-01-31, weekly, monday, 10 times"},
+		{name: "from and until", input: "every day from tomorrow until next week", exp: "2024-02-01, daily, until 2024-02-05"},
+		{name: "empty"},
+		{name: "every", input: "every"},
+		{name: "unknown", input: "sometimes"},
+		{name: "zero interval", input: "every 0 days"},
+		{name: "unknown weekday", input: "every monday and someday"},
+		{name: "invalid month day", input: "monthly on the 32nd"},
+		{name: "interval with last day", input: "every 2 months on the last day"},
+		{name: "invalid until", input: "daily until never"},
+		{name: "invalid from", input: "daily from never"},
+		{name: "zero times", input: "daily 0 times"},
+		{name: "too many times", input: "daily 20000000 times"},
+		{name: "weekly on", input: "weekly on monday"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			act := item.ParseRecurrer(tc.input, ref)
+			if tc.exp == "" {
+				if act != nil {
+					t.Errorf("exp nil, got %v", act.String())
+				}
+				return
+			}
+			if act == nil {
+				t.Errorf("exp %v, got nil", tc.exp)
+				return
+			}
+			if act.String() != tc.exp {
+				t.Errorf("exp %v, got %v", tc.exp, act.String())
+			}
+			// the canonical form is what gets stored, it must parse back
+			if back := item.NewRecurrer(act.String()); back == nil || back.String() != tc.exp {
+				t.Errorf("exp %v, got %v", tc.exp, back)
+			}
+		})
+	}
+}
+
+func TestParseRecurrerShortMonth(t *testing.T) {
+	t.Parallel()
+
+	// february has no 31st, so it starts in march, and april overflows
+	// into may
+	rec := item.ParseRecurrer("monthly on the 31st", item.NewDate(2024, 2, 1))
+	if rec == nil {
+		t.Fatalf("exp recurrer, got nil")
+	}
+	exp := []item.Date{item.NewDate(2024, 3, 31), item.NewDate(2024, 5, 1), item.NewDate(2024, 5, 31)}
+	act := item.Occurrences(rec, item.NewDate(2024, 2, 1), 3)
+	if len(act) != len(exp) {
+		t.Fatalf("exp %v, got %v", exp, act)
+	}
+	for i := range exp {
+		if !act[i].Equal(exp[i]) {
+			t.Errorf("exp %v, got %v", exp[i], act[i])
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package item
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupportedRRule = errors.New("unsupported rrule")
+)
+
+const (
+	rruleDateFormat = "20060102"
+)
+
+var rruleWeekdays = map[time.Weekday]string{
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+	time.Sunday:    "SU",
+}
+
+var workWeek = Weekdays{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// ToRRule converts a recurrer to the value of an iCalendar RRULE, as in RFC
+// 5545, and the dates it skips. The start date is not part of it, in
+// iCalendar that goes in DTSTART. The skipped dates go in EXDATE.
+func ToRRule(r Recurrer) (string, []Date, error) {
+	switch rec := r.(type) {
+	case Daily:
+		return "FREQ=DAILY", nil, nil
+	case EveryNDays:
+		return fmt.Sprintf("FREQ=DAILY;INTERVAL=%d", rec.N), nil, nil
+	case Weekly:
+		return fmt.Sprintf("FREQ=WEEKLY;BYDAY=%s", rruleByDay(rec.Weekdays)), nil, nil
+	case EveryNWeeks:
+		return fmt.Sprintf("FREQ=WEEKLY;INTERVAL=%d", rec.N), nil, nil
+	case EveryNMonths:
+		return fmt.Sprintf("FREQ=MONTHLY;INTERVAL=%d", rec.N), nil, nil
+	case MonthlyWeekday:
+		return fmt.Sprintf("FREQ=MONTHLY;BYDAY=%d%s", rec.Nth, rruleWeekdays[rec.Weekday]), nil, nil
+	case MonthlyLastDay:
+		return "FREQ=MONTHLY;BYMONTHDAY=-1", nil, nil
+	case Yearly:
+		return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYMONTHDAY=%d", rec.Month, rec.Day), nil, nil
+	case EveryWeekday:
+		return fmt.Sprintf("FREQ=DAILY;BYDAY=%s", rruleByDay(workWeek)), nil, nil
+	case Except:
+		rrule, exdates, err := ToRRule(rec.Recurrer)
+		if err != nil {
+			return "", nil, err
+		}
+		return rrule, sortDates(append(exdates, rec.Dates...)), nil
+	case Ending:
+		rrule, exdates, err := ToRRule(rec.Recurrer)
+		if err != nil {
+			return "", nil, err
+		}
+		if rec.Count > 0 {
+			return fmt.Sprintf("%s;COUNT=%d", rrule, rec.Count), exdates, nil
+		}
+		return fmt.Sprintf("%s;UNTIL=%s", rrule, rec.Until.Time().Format(rruleDateFormat)), exdates, nil
+	default:
+		return "", nil, fmt.Errorf("%w: unknown recurrer %T", ErrUnsupportedRRule, r)
+	}
+}
+
+// NewRecurrerFromRRule creates a recurrer from the value of an iCalendar
+// RRULE that starts on start and skips the dates in exdates, the values of
+// EXDATE. Only the rules that can be expressed as a recurrer are supported,
+// others return ErrUnsupportedRRule.
+func NewRecurrerFromRRule(start Date, rrule string, exdates ...Date) (Recurrer, error) {
+	if start.IsZero() {
+		return nil, fmt.Errorf("%w: no start date", ErrUnsupportedRRule)
+	}
+
+	parts := make(map[string]string)
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(rrule), "RRULE:"), ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid part %q", ErrUnsupportedRRule, part)
+		}
+		key = strings.ToUpper(key)
+		switch key {
+		case "FREQ", "INTERVAL", "BYDAY", "BYMONTHDAY", "BYMONTH", "UNTIL", "COUNT":
+		case "WKST":
+			// only affects weekly rules with both an interval and days,
+			// and those are not supported anyway
+			continue
+		default:
+			return nil, fmt.Errorf("%w: %s is not supported", ErrUnsupportedRRule, key)
+		}
+		parts[key] = strings.ToUpper(val)
+	}
+
+	interval := 1
+	if val, ok := parts["INTERVAL"]; ok {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("%w: invalid interval %q", ErrUnsupportedRRule, val)
+		}
+		interval = n
+	}
+
+	rec, err := rruleRecurrer(start, parts, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	_, hasUntil := parts["UNTIL"]
+	_, hasCount := parts["COUNT"]
+	switch {
+	case hasUntil && hasCount:
+		return nil, fmt.Errorf("%w: both until and count", ErrUnsupportedRRule)
+	case hasUntil:
+		val := parts["UNTIL"]
+		if len(val) < len(rruleDateFormat) {
+			return nil, fmt.Errorf("%w: invalid until %q", ErrUnsupportedRRule, val)
+		}
+		until, err := time.Parse(rruleDateFormat, val[:len(rruleDateFormat)])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid until %q", ErrUnsupportedRRule, val)
+		}
+		rec = NewEnding(rec, NewDate(until.Year(), int(until.Month()), until.Day()), 0)
+	case hasCount:
+		count, err := strconv.Atoi(parts["COUNT"])
+		if err != nil || count < 1 || count > MaxCount {
+			return nil, fmt.Errorf("%w: invalid count %q", ErrUnsupportedRRule, parts["COUNT"])
+		}
+		rec = NewEnding(rec, Date{}, count)
+	}
+
+	if len(exdates) > 0 {
+		rec = Except{Recurrer: rec, Dates: sortDates(slices.Clone(exdates))}
+	}
+
+	return rec, nil
+}
+
+func rruleRecurrer(start Date, parts map[string]string, interval int) (Recurrer, error) {
+	byDay, hasByDay := parts["BYDAY"]
+	byMonthDay, hasByMonthDay := parts["BYMONTHDAY"]
+	byMonth, hasByMonth := parts["BYMONTH"]
+
+	switch freq := parts["FREQ"]; freq {
+	case "DAILY":
+		if hasByMonthDay || hasByMonth {
+			return nil, fmt.Errorf("%w: daily rule with month parts", ErrUnsupportedRRule)
+		}
+		if hasByDay {
+			wds, err := parseRRuleWeekdays(byDay)
+			if err != nil {
+				return nil, err
+			}
+			if interval != 1 || !slices.Equal(wds, workWeek) {
+				return nil, fmt.Errorf("%w: daily rule with days other than weekdays", ErrUnsupportedRRule)
+			}
+			return EveryWeekday{Start: start}, nil
+		}
+		if interval == 1 {
+			return Daily{Start: start}, nil
+		}
+		return EveryNDays{Start: start, N: interval}, nil
+
+	case "WEEKLY":
+		if hasByMonthDay || hasByMonth {
+			return nil, fmt.Errorf("%w: weekly rule with month parts", ErrUnsupportedRRule)
+		}
+		if !hasByDay {
+			return EveryNWeeks{Start: start, N: interval}, nil
+		}
+		if interval != 1 {
+			return nil, fmt.Errorf("%w: weekly rule with both interval and days", ErrUnsupportedRRule)
+		}
+		wds, err := parseRRuleWeekdays(byDay)
+		if err != nil {
+			return nil, err
+		}
+		return Weekly{Start: start, Weekdays: wds}, nil
+
+	case "MONTHLY":
+		switch {
+		case hasByMonth || (hasByDay && hasByMonthDay):
+			return nil, fmt.Errorf("%w: monthly rule with too many parts", ErrUnsupportedRRule)
+		case hasByDay:
+			if interval != 1 {
+				return nil, fmt.Errorf("%w: monthly rule with both interval and days", ErrUnsupportedRRule)
+			}
+			return parseRRuleMonthlyWeekday(start, byDay)
+		case byMonthDay == "-1":
+			if interval != 1 {
+				return nil, fmt.Errorf("%w: monthly rule with both interval and last day", ErrUnsupportedRRule)
+			}
+			return MonthlyLastDay{Start: start}, nil
+		case hasByMonthDay && byMonthDay != strconv.Itoa(start.Day()):
+			return nil, fmt.Errorf("%w: month day %s differs from start date", ErrUnsupportedRRule, byMonthDay)
+		default:
+			return EveryNMonths{Start: start, N: interval}, nil
+		}
+
+	case "YEARLY":
+		if hasByDay || interval != 1 {
+			return nil, fmt.Errorf("%w: yearly rule with days or interval", ErrUnsupportedRRule)
+		}
+		month, day := start.Time().Month(), start.Day()
+		if hasByMonth {
+			m, err := strconv.Atoi(byMonth)
+			if err != nil || m < 1 || m > 12 {
+				return nil, fmt.Errorf("%w: invalid month %q", ErrUnsupportedRRule, byMonth)
+			}
+			month = time.Month(m)
+		}
+		if hasByMonthDay {
+			d, err := strconv.Atoi(byMonthDay)
+			if err != nil || d < 1 || d > 31 {
+				return nil, fmt.Errorf("%w: invalid month day %q", ErrUnsupportedRRule, byMonthDay)
+			}
+			day = d
+		}
+		return Yearly{Start: start, Month: month, Day: day}, nil
+
+	case "":
+		return nil, fmt.Errorf("%w: missing frequency", ErrUnsupportedRRule)
+	default:
+		return nil, fmt.Errorf("%w: frequency %s", ErrUnsupportedRRule, freq)
+	}
+}
+
+func rruleByDay(wds Weekdays) string {
+	codes := make([]string, 0, len(wds))
+	for _, wd := range wds {
+		codes = append(codes, rruleWeekdays[wd])
+	}
+
+	return strings.Join(codes, ",")
+}
+
+func parseRRuleWeekday(code string) (time.Weekday, bool) {
+	for wd, c := range rruleWeekdays {
+		if c == code {
+			return wd, true
+		}
+	}
+
+	return time.Sunday, false
+}
+
+func parseRRuleWeekdays(byDay string) (Weekdays, error) {
+	wds := Weekdays{}
+	for _, code := range strings.Split(byDay, ",") {
+		wd, ok := parseRRuleWeekday(code)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid day %q", ErrUnsupportedRRule, code)
+		}
+		wds = append(wds, wd)
+	}
+
+	return wds.Unique(), nil
+}
+
+// 2TU, -1FR
+func parseRRuleMonthlyWeekday(start Date, byDay string) (Recurrer, error) {
+	if len(byDay) < 3 || strings.Contains(byDay, ",") {
+		return nil, fmt.Errorf("%w: monthly rule needs one day with a position, got %q", ErrUnsupportedRRule, byDay)
+	}
+	nth, err := strconv.Atoi(byDay[:len(byDay)-2])
+	if err != nil || nth == 0 || nth < -1 || nth > 5 {
+		return nil, fmt.Errorf("%w: invalid position in %q", ErrUnsupportedRRule, byDay)
+	}
+	wd, ok := parseRRuleWeekday(byDay[len(byDay)-2:])
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid day in %q", ErrUnsupportedRRule, byDay)
+	}
+
+	return MonthlyWeekday{Start: start, Nth: nth, Weekday: wd}, nil
+}
@@ -0,0 +1,219 @@
+package item_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go-mod.ewintr.nl/planner/item"
+)
+
+func TestRRuleRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	start := item.NewDate(2024, 1, 1)
+	for _, tc := range []struct {
+		name       string
+		recurrer   item.Recurrer
+		expRRule   string
+		expExDates []item.Date
+	}{
+		{
+			name:     "daily",
+			recurrer: item.Daily{Start: start},
+			expRRule: "FREQ=DAILY",
+		},
+		{
+			name:     "every n days",
+			recurrer: item.EveryNDays{Start: start, N: 3},
+			expRRule: "FREQ=DAILY;INTERVAL=3",
+		},
+		{
+			name:     "weekly",
+			recurrer: item.Weekly{Start: start, Weekdays: item.Weekdays{time.Monday, time.Thursday}},
+			expRRule: "FREQ=WEEKLY;BYDAY=MO,TH",
+		},
+		{
+			name:     "every n weeks",
+			recurrer: item.EveryNWeeks{Start: start, N: 2},
+			expRRule: "FREQ=WEEKLY;INTERVAL=2",
+		},
+		{
+			name:     "every n months",
+			recurrer: item.EveryNMonths{Start: start, N: 3},
+			expRRule: "FREQ=MONTHLY;INTERVAL=3",
+		},
+		{
+			name:     "monthly weekday",
+			recurrer: item.MonthlyWeekday{Start: start, Nth: 2, Weekday: time.Tuesday},
+			expRRule: "FREQ=MONTHLY;BYDAY=2TU",
+		},
+		{
+			name:     "monthly last weekday",
+			recurrer: item.MonthlyWeekday{Start: start, Nth: -1, Weekday: time.Friday},
+			expRRule: "FREQ=MONTHLY;BYDAY=-1FR",
+		},
+		{
+			name:     "monthly last day",
+			recurrer: item.MonthlyLastDay{Start: start},
+			expRRule: "FREQ=MONTHLY;BYMONTHDAY=-1",
+		},
+		{
+			name:     "yearly",
+			recurrer: item.Yearly{Start: start, Month: time.March, Day: 15},
+			expRRule: "FREQ=YEARLY;BYMONTH=3;BYMONTHDAY=15",
+		},
+		{
+			name:     "every weekday",
+			recurrer: item.EveryWeekday{Start: start},
+			expRRule: "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+		},
+		{
+			name:     "until",
+			recurrer: item.NewEnding(item.Daily{Start: start}, item.NewDate(2024, 6, 30), 0),
+			expRRule: "FREQ=DAILY;UNTIL=20240630",
+		},
+		{
+			name:     "count",
+			recurrer: item.NewEnding(item.Weekly{Start: start, Weekdays: item.Weekdays{time.Monday}}, item.Date{}, 10),
+			expRRule: "FREQ=WEEKLY;BYDAY=MO;COUNT=10",
+		},
+		{
+			name:       "except",
+			recurrer:   item.WithException(item.WithException(item.Daily{Start: start}, item.NewDate(2024, 1, 5)), item.NewDate(2024, 1, 3)),
+			expRRule:   "FREQ=DAILY",
+			expExDates: []item.Date{item.NewDate(2024, 1, 3), item.NewDate(2024, 1, 5)},
+		},
+		{
+			name:       "except with count",
+			recurrer:   item.WithException(item.NewEnding(item.Daily{Start: start}, item.Date{}, 10), item.NewDate(2024, 1, 3)),
+			expRRule:   "FREQ=DAILY;COUNT=10",
+			expExDates: []item.Date{item.NewDate(2024, 1, 3)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actRRule, actExDates, err := item.ToRRule(tc.recurrer)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if tc.expRRule != actRRule {
+				t.Errorf("exp %v, got %v", tc.expRRule, actRRule)
+			}
+			if diff := cmp.Diff(tc.expExDates, actExDates); diff != "" {
+				t.Errorf("(-exp +got):\n%s", diff)
+			}
+			actRecurrer, err := item.NewRecurrerFromRRule(start, actRRule, actExDates...)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if diff := cmp.Diff(tc.recurrer, actRecurrer); diff != "" {
+				t.Errorf("(-exp +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewRecurrerFromRRule(t *testing.T) {
+	t.Parallel()
+
+	start := item.NewDate(2024, 1, 15)
+	for _, tc := range []struct {
+		name   string
+		rrule  string
+		exp    item.Recurrer
+		expErr bool
+	}{
+		{
+			name:  "prefix and lower case",
+			rrule: "RRULE:freq=weekly;byday=we",
+			exp:   item.Weekly{Start: start, Weekdays: item.Weekdays{time.Wednesday}},
+		},
+		{
+			name:  "until with time",
+			rrule: "FREQ=DAILY;UNTIL=20240630T235959Z",
+			exp:   item.NewEnding(item.Daily{Start: start}, item.NewDate(2024, 6, 30), 0),
+		},
+		{
+			name:  "week start",
+			rrule: "FREQ=WEEKLY;INTERVAL=2;WKST=SU",
+			exp:   item.EveryNWeeks{Start: start, N: 2},
+		},
+		{
+			name:  "month day of start",
+			rrule: "FREQ=MONTHLY;BYMONTHDAY=15",
+			exp:   item.EveryNMonths{Start: start, N: 1},
+		},
+		{
+			name:  "yearly from start",
+			rrule: "FREQ=YEARLY",
+			exp:   item.Yearly{Start: start, Month: time.January, Day: 15},
+		},
+		{
+			name:   "missing frequency",
+			rrule:  "INTERVAL=2",
+			expErr: true,
+		},
+		{
+			name:   "hourly",
+			rrule:  "FREQ=HOURLY",
+			expErr: true,
+		},
+		{
+			name:   "unknown part",
+			rrule:  "FREQ=MONTHLY;BYDAY=MO,TU;BYSETPOS=-1",
+			expErr: true,
+		},
+		{
+			name:   "weekly interval and days",
+			rrule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			expErr: true,
+		},
+		{
+			name:   "other month day",
+			rrule:  "FREQ=MONTHLY;BYMONTHDAY=3",
+			expErr: true,
+		},
+		{
+			name:   "multiple monthly days",
+			rrule:  "FREQ=MONTHLY;BYDAY=1MO,3MO",
+			expErr: true,
+		},
+		{
+			name:   "until and count",
+			rrule:  "FREQ=DAILY;UNTIL=20240630;COUNT=3",
+			expErr: true,
+		},
+		{
+			name:   "too many times",
+			rrule:  "FREQ=DAILY;COUNT=20000000",
+			expErr: true,
+		},
+		{
+			name:   "invalid day",
+			rrule:  "FREQ=WEEKLY;BYDAY=XX",
+			expErr: true,
+		},
+		{
+			name:   "invalid part",
+			rrule:  "FREQ=DAILY;COUNT",
+			expErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			act, err := item.NewRecurrerFromRRule(start, tc.rrule)
+			if tc.expErr {
+				if !errors.Is(err, item.ErrUnsupportedRRule) {
+					t.Errorf("exp %v, got %v", item.ErrUnsupportedRRule, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if diff := cmp.Diff(tc.exp, act); diff != "" {
+				t.Errorf("(-exp +got):\n%s", diff)
+			}
+		})
+	}
+}
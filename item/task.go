@@ -3,6 +3,8 @@ package item
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -13,6 +15,23 @@ type TaskBody struct {
 	Project  string        `json:"project"`
 	Time     Time          `json:"time"`
 	Duration time.Duration `json:"duration"`
+	Priority Priority      `json:"priority"`
+	Created  Date          `json:"created"`
+	Deadline Date          `json:"deadline"`
+	// SnoozeUntil hides the task from lists before that date
+	SnoozeUntil Date `json:"snoozeUntil"`
+	// Postponed counts the times the task was rolled over to a later day,
+	// RolledOver is the last day that happened, so it is counted once a day
+	Postponed  int      `json:"postponed"`
+	RolledOver Date     `json:"rolledOver"`
+	Tags       []string `json:"tags"`
+	Notes      string   `json:"notes"`
+	// Parent is the id of the task this is a subtask of, Position its place
+	// among the subtasks of that parent
+	Parent   string `json:"parent"`
+	Position int    `json:"position"`
+	// BlockedBy holds the ids of the tasks that must be done first
+	BlockedBy []string `json:"blockedBy"`
 }
 
 func (e TaskBody) MarshalJSON() ([]byte, error) {
@@ -47,10 +66,11 @@ func (e *TaskBody) UnmarshalJSON(data []byte) error {
 }
 
 type Task struct {
-	ID        string   `json:"id"`
-	Date      Date     `json:"date"`
-	Recurrer  Recurrer `json:"recurrer"`
-	RecurNext Date     `json:"recurNext"`
+	ID          string   `json:"id"`
+	Date        Date     `json:"date"`
+	Recurrer    Recurrer `json:"recurrer"`
+	RecurNext   Date     `json:"recurNext"`
+	RecurParent string   `json:"recurParent"`
 	TaskBody
 }
 
@@ -68,6 +88,7 @@ func NewTask(i Item) (Task, error) {
 	t.Date = i.Date
 	t.Recurrer = i.Recurrer
 	t.RecurNext = i.RecurNext
+	t.RecurParent = i.RecurParent
 
 	return t, nil
 }
@@ -79,15 +100,42 @@ func (t Task) Item() (Item, error) {
 	}
 
 	return Item{
-		ID:        t.ID,
-		Kind:      KindTask,
-		Date:      t.Date,
-		Recurrer:  t.Recurrer,
-		RecurNext: t.RecurNext,
-		Body:      string(body),
+		ID:          t.ID,
+		Kind:        KindTask,
+		Date:        t.Date,
+		Recurrer:    t.Recurrer,
+		RecurNext:   t.RecurNext,
+		RecurParent: t.RecurParent,
+		Body:        string(body),
 	}, nil
 }
 
+// NewTags cleans up tags as they are typed in. A leading @ is dropped, they
+// are lowercased, and the result is sorted and without duplicates. It is nil
+// if there are no tags.
+func NewTags(tags ...string) []string {
+	var res []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "@"))
+		if tag == "" {
+			continue
+		}
+		res = append(res, tag)
+	}
+	slices.Sort(res)
+
+	return slices.Compact(res)
+}
+
+func (t Task) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, tag)
+}
+
+// Hidden tells whether the task is snoozed on the given day
+func (t Task) Hidden(today Date) bool {
+	return t.SnoozeUntil.After(today)
+}
+
 func (t Task) Valid() bool {
 	if t.Title == "" {
 		return false
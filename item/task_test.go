@@ -104,7 +104,7 @@ func TestTaskItem(t *testing.T) {
 			expItem: item.Item{
 				Kind:    item.KindTask,
 				Updated: time.Time{},
-				Body:    `{"duration":"0s","title":"","project":"","time":""}`,
+				Body:    `{"duration":"0s","title":"","project":"","time":"","priority":0,"created":"","deadline":"","snoozeUntil":"","postponed":0,"rolledOver":"","tags":null,"notes":"","parent":"","position":0,"blockedBy":null}`,
 			},
 		},
 		{
@@ -124,7 +124,7 @@ func TestTaskItem(t *testing.T) {
 				Kind:    item.KindTask,
 				Updated: time.Time{},
 				Date:    item.NewDate(2024, 9, 23),
-				Body:    `{"duration":"1h0m0s","title":"title","project":"project","time":"08:00"}`,
+				Body:    `{"duration":"1h0m0s","title":"title","project":"project","time":"08:00","priority":0,"created":"","deadline":"","snoozeUntil":"","postponed":0,"rolledOver":"","tags":null,"notes":"","parent":"","position":0,"blockedBy":null}`,
 			},
 		},
 	} {
@@ -192,3 +192,33 @@ func TestTaskValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTags(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name  string
+		input []string
+		exp   []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:  "clean up",
+			input: []string{"@Phone", " home", "", "@"},
+			exp:   []string{"home", "phone"},
+		},
+		{
+			name:  "duplicates",
+			input: []string{"home", "@home", "HOME"},
+			exp:   []string{"home"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.exp, item.NewTags(tc.input...)); diff != "" {
+				t.Errorf("(exp +, got -)\n%s", diff)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package item
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -9,8 +11,12 @@ const (
 	TimeFormat = "15:04"
 )
 
+// Time is a time of the day. Without a location it is a floating time that
+// is interpreted in the timezone of the user. With a location it is fixed to
+// that zone, e.g. for an appointment in another timezone.
 type Time struct {
-	t time.Time
+	t   time.Time
+	loc *time.Location
 }
 
 func (t Time) MarshalJSON() ([]byte, error) {
@@ -24,6 +30,7 @@ func (t *Time) UnmarshalJSON(data []byte) error {
 	}
 	nt := NewTimeFromString(timeString)
 	t.t = nt.Time()
+	t.loc = nt.Location()
 
 	return nil
 }
@@ -34,21 +41,43 @@ func NewTime(hour, minute int) Time {
 	}
 }
 
+// NewTimeIn creates a time of the day that is fixed to loc
+func NewTimeIn(hour, minute int, loc *time.Location) Time {
+	t := NewTime(hour, minute)
+	t.loc = loc
+
+	return t
+}
+
+// NewTimeFromString parses "15:04", or "15:04 Europe/Amsterdam" for a time
+// in a specific zone
 func NewTimeFromString(timeStr string) Time {
+	timeStr, zoneStr, _ := strings.Cut(strings.TrimSpace(timeStr), " ")
 	tm, err := time.Parse(TimeFormat, timeStr)
 	if err != nil {
 		return Time{t: time.Time{}}
 	}
+	zoneStr = strings.TrimSpace(zoneStr)
+	if zoneStr == "" {
+		return Time{t: tm}
+	}
+	loc, err := time.LoadLocation(zoneStr)
+	if err != nil {
+		return Time{t: time.Time{}}
+	}
 
-	return Time{t: tm}
+	return Time{t: tm, loc: loc}
 }
 
 func (t *Time) String() string {
 	if t.t.IsZero() {
 		return ""
 	}
+	if t.loc == nil {
+		return t.t.Format(TimeFormat)
+	}
 
-	return t.t.Format(TimeFormat)
+	return fmt.Sprintf("%s %s", t.t.Format(TimeFormat), t.loc.String())
 }
 
 func (t *Time) Time() time.Time {
@@ -67,8 +96,25 @@ func (t *Time) Minute() int {
 	return t.t.Minute()
 }
 
+// Location returns the zone the time is fixed to, or nil for a floating time
+func (t *Time) Location() *time.Location {
+	return t.loc
+}
+
+// On returns the moment this time occurs on date d
+func (t *Time) On(d Date) time.Time {
+	loc := t.loc
+	if loc == nil {
+		loc = Timezone()
+	}
+	year, month, day := d.Time().Date()
+
+	return time.Date(year, month, day, t.Hour(), t.Minute(), 0, 0, loc)
+}
+
 func (t *Time) Add(d time.Duration) Time {
 	return Time{
-		t: t.t.Add(d),
+		t:   t.t.Add(d),
+		loc: t.loc,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"go-mod.ewintr.nl/planner/item"
 )
@@ -70,3 +71,87 @@ func TestTimeFromString(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeZone(t *testing.T) {
+	t.Parallel()
+
+	ams, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+
+	t.Run("string", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			str    string
+			exp    string
+			expLoc string
+		}{
+			{
+				name: "floating",
+				str:  "09:30",
+				exp:  "09:30",
+			},
+			{
+				name:   "zoned",
+				str:    "09:30 America/New_York",
+				exp:    "09:30 America/New_York",
+				expLoc: "America/New_York",
+			},
+			{
+				name: "unknown zone",
+				str:  "09:30 Middle/Earth",
+				exp:  "",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				act := item.NewTimeFromString(tc.str)
+				if tc.exp != act.String() {
+					t.Errorf("exp %v, got %v", tc.exp, act.String())
+				}
+				var actLoc string
+				if act.Location() != nil {
+					actLoc = act.Location().String()
+				}
+				if tc.expLoc != actLoc {
+					t.Errorf("exp %v, got %v", tc.expLoc, actLoc)
+				}
+			})
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		tm := item.NewTimeIn(9, 30, ny)
+		actJSON, err := json.Marshal(tm)
+		if err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		if string(actJSON) != `"09:30 America/New_York"` {
+			t.Errorf("exp zoned time, got %v", string(actJSON))
+		}
+		var actTM item.Time
+		if err := json.Unmarshal(actJSON, &actTM); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		if tm.String() != actTM.String() {
+			t.Errorf("exp %v, got %v", tm.String(), actTM.String())
+		}
+	})
+
+	t.Run("on", func(t *testing.T) {
+		date := item.NewDate(2024, 7, 1)
+		zoned := item.NewTimeIn(9, 30, ny)
+		exp := time.Date(2024, 7, 1, 15, 30, 0, 0, ams)
+		if act := zoned.On(date); !exp.Equal(act) {
+			t.Errorf("exp %v, got %v", exp, act)
+		}
+		floating := item.NewTime(9, 30)
+		if act := floating.On(date); act.Hour() != 9 || act.Minute() != 30 || act.Location() != item.Timezone() {
+			t.Errorf("exp 09:30 in user timezone, got %v", act)
+		}
+	})
+}
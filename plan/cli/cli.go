@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"go-mod.ewintr.nl/planner/item"
 	"go-mod.ewintr.nl/planner/plan/cli/arg"
 	"go-mod.ewintr.nl/planner/plan/command"
 	"go-mod.ewintr.nl/planner/plan/command/schedule"
@@ -17,16 +18,41 @@ type CLI struct {
 	cmdArgs []command.CommandArgs
 }
 
-func NewCLI(repos command.Repositories, client client.Client) *CLI {
+// Options are the settings from the configuration that change how commands
+// behave
+type Options struct {
+	// AutoRollover moves unfinished tasks from past days to today on each
+	// sync
+	AutoRollover bool
+	// DeadlineWindow is the number of days before a deadline in which it is
+	// pointed out, zero for the default
+	DeadlineWindow int
+	// DayStart and DayEnd are the working hours that the day planner uses,
+	// zero for nine to five
+	DayStart item.Time
+	DayEnd   item.Time
+}
+
+// NewCLI sets up the commands
+func NewCLI(repos command.Repositories, client client.Client, opts Options) *CLI {
+	beforeSync := make([]command.Command, 0)
+	if opts.AutoRollover {
+		beforeSync = append(beforeSync, task.Rollover{})
+	}
+
 	return &CLI{
 		repos:  repos,
 		client: client,
 		cmdArgs: []command.CommandArgs{
-			command.NewSyncArgs(),
+			command.NewSyncArgs(beforeSync...),
 			// task
-			task.NewShowArgs(), task.NewProjectsArgs(),
-			task.NewAddArgs(), task.NewDeleteArgs(), task.NewListArgs(),
-			task.NewUpdateArgs(),
+			task.NewShowArgs(opts.DeadlineWindow), task.NewProjectsArgs(), task.NewTagsArgs(),
+			task.NewAddArgs(), task.NewQuickAddArgs(), task.NewDeleteArgs(), task.NewListArgs(opts.DeadlineWindow),
+			task.NewNextArgs(), task.NewOverdueArgs(opts.DeadlineWindow), task.NewRolloverArgs(),
+			task.NewDayArgs(opts.DayStart, opts.DayEnd),
+			task.NewUpdateArgs(), task.NewEditArgs(), task.NewSkipArgs(),
+			task.NewSnoozeArgs(),
+			task.NewRecurPreviewArgs(), task.NewOccurrencesArgs(),
 			// schedule
 			schedule.NewAddArgs(),
 		},
@@ -36,7 +62,13 @@ func NewCLI(repos command.Repositories, client client.Client) *CLI {
 func (cli *CLI) Run(args []string) error {
 	main, fields := arg.FindFields(args)
 	for _, ca := range cli.cmdArgs {
-		cmd, err := ca.Parse(main, fields)
+		var cmd command.Command
+		var err error
+		if raw, ok := ca.(command.RawCommandArgs); ok {
+			cmd, err = raw.ParseRaw(args)
+		} else {
+			cmd, err = ca.Parse(main, fields)
+		}
 		switch {
 		case errors.Is(err, command.ErrWrongCommand):
 			continue
@@ -29,6 +29,12 @@ type CommandArgs interface {
 	Parse(main []string, fields map[string]string) (Command, error)
 }
 
+// RawCommandArgs is implemented by commands that need the arguments as they
+// were typed, before the fields are split off
+type RawCommandArgs interface {
+	ParseRaw(args []string) (Command, error)
+}
+
 type Command interface {
 	Do(repos Repositories, client client.Client) (CommandResult, error)
 }
@@ -60,7 +60,7 @@ func (aa AddArgs) Parse(main []string, fields map[string]string) (command.Comman
 		sched.Date = d
 	}
 	if val, ok := fields["recurrer"]; ok {
-		rec := item.NewRecurrer(val)
+		rec := item.ParseRecurrer(val, item.Today())
 		if rec == nil {
 			return nil, fmt.Errorf("%w: could not parse recurrer", command.ErrInvalidArg)
 		}
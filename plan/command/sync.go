@@ -11,10 +11,16 @@ import (
 	"go-mod.ewintr.nl/planner/sync/client"
 )
 
-type SyncArgs struct{}
+type SyncArgs struct {
+	before []Command
+}
 
-func NewSyncArgs() SyncArgs {
-	return SyncArgs{}
+// NewSyncArgs takes commands that run before each sync, so that what they
+// change is sent along
+func NewSyncArgs(before ...Command) SyncArgs {
+	return SyncArgs{
+		before: before,
+	}
 }
 
 func (sa SyncArgs) Parse(main []string, flags map[string]string) (Command, error) {
@@ -22,38 +28,87 @@ func (sa SyncArgs) Parse(main []string, flags map[string]string) (Command, error
 		return nil, ErrWrongCommand
 	}
 
-	return &Sync{}, nil
+	return &Sync{
+		before: sa.before,
+	}, nil
 }
 
-type Sync struct{}
+type Sync struct {
+	before []Command
+}
 
+// Do sends the local changes and receives those made elsewhere before the
+// commands that run before the sync, so they see the latest state. What they
+// change is sent after that.
 func (s Sync) Do(repos Repositories, client client.Client) (CommandResult, error) {
 	tx, err := repos.Begin()
 	if err != nil {
 		return nil, fmt.Errorf("could not start transaction: %v", err)
 	}
 	defer tx.Rollback()
+	if err := send(repos, tx, client); err != nil {
+		return nil, err
+	}
+	if err := receive(repos, tx, client); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not sync items: %v", err)
+	}
 
-	// local new and updated
+	beforeResults := make([]CommandResult, 0, len(s.before))
+	for _, cmd := range s.before {
+		res, err := cmd.Do(repos, client)
+		if err != nil {
+			return nil, err
+		}
+		beforeResults = append(beforeResults, res)
+	}
+	if len(s.before) > 0 {
+		tx, err := repos.Begin()
+		if err != nil {
+			return nil, fmt.Errorf("could not start transaction: %v", err)
+		}
+		defer tx.Rollback()
+		if err := send(repos, tx, client); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("could not sync items: %v", err)
+		}
+	}
+
+	return SyncResult{
+		Before: beforeResults,
+	}, nil
+}
+
+// send sends the local new and updated items
+func send(repos Repositories, tx *storage.Tx, client client.Client) error {
 	sendItems, err := repos.Sync(tx).FindAll()
 	if err != nil {
-		return nil, fmt.Errorf("could not get updated items: %v", err)
+		return fmt.Errorf("could not get updated items: %v", err)
 	}
 	if err := client.Update(sendItems); err != nil {
-		return nil, fmt.Errorf("could not send updated items: %v", err)
+		return fmt.Errorf("could not send updated items: %v", err)
 	}
 	if err := repos.Sync(tx).DeleteAll(); err != nil {
-		return nil, fmt.Errorf("could not clear updated items: %v", err)
+		return fmt.Errorf("could not clear updated items: %v", err)
 	}
 
-	// get new/updated items
+	return nil
+}
+
+// receive stores the items that were added, updated or deleted elsewhere
+// since the last time
+func receive(repos Repositories, tx *storage.Tx, client client.Client) error {
 	oldTS, err := repos.Sync(tx).LastUpdate()
 	if err != nil {
-		return nil, fmt.Errorf("could not find timestamp of last update: %v", err)
+		return fmt.Errorf("could not find timestamp of last update: %v", err)
 	}
 	recItems, err := client.Updated([]item.Kind{item.KindTask}, oldTS)
 	if err != nil {
-		return nil, fmt.Errorf("could not receive updates: %v", err)
+		return fmt.Errorf("could not receive updates: %v", err)
 	}
 
 	updated := make([]item.Item, 0)
@@ -64,10 +119,10 @@ func (s Sync) Do(repos Repositories, client client.Client) (CommandResult, error
 		}
 		if ri.Deleted {
 			if err := repos.LocalID(tx).Delete(ri.ID); err != nil && !errors.Is(err, storage.ErrNotFound) {
-				return nil, fmt.Errorf("could not delete local id: %v", err)
+				return fmt.Errorf("could not delete local id: %v", err)
 			}
 			if err := repos.Task(tx).Delete(ri.ID); err != nil && !errors.Is(err, storage.ErrNotFound) {
-				return nil, fmt.Errorf("could not delete task: %v", err)
+				return fmt.Errorf("could not delete task: %v", err)
 			}
 			continue
 		}
@@ -76,47 +131,53 @@ func (s Sync) Do(repos Repositories, client client.Client) (CommandResult, error
 
 	lidMap, err := repos.LocalID(tx).FindAll()
 	if err != nil {
-		return nil, fmt.Errorf("could not get local ids: %v", err)
+		return fmt.Errorf("could not get local ids: %v", err)
 	}
 	for _, u := range updated {
 		var tskBody item.TaskBody
 		if err := json.Unmarshal([]byte(u.Body), &tskBody); err != nil {
-			return nil, fmt.Errorf("could not unmarshal task body: %v", err)
+			return fmt.Errorf("could not unmarshal task body: %v", err)
 		}
 		tsk := item.Task{
-			ID:        u.ID,
-			Date:      u.Date,
-			Recurrer:  u.Recurrer,
-			RecurNext: u.RecurNext,
-			TaskBody:  tskBody,
+			ID:          u.ID,
+			Date:        u.Date,
+			Recurrer:    u.Recurrer,
+			RecurNext:   u.RecurNext,
+			RecurParent: u.RecurParent,
+			TaskBody:    tskBody,
 		}
 		if err := repos.Task(tx).Store(tsk); err != nil {
-			return nil, fmt.Errorf("could not store task: %v", err)
+			return fmt.Errorf("could not store task: %v", err)
 		}
 		lid, ok := lidMap[u.ID]
 		if !ok {
 			lid, err = repos.LocalID(tx).Next()
 			if err != nil {
-				return nil, fmt.Errorf("could not get next local id: %v", err)
+				return fmt.Errorf("could not get next local id: %v", err)
 			}
 
 			if err := repos.LocalID(tx).Store(u.ID, lid); err != nil {
-				return nil, fmt.Errorf("could not store local id: %v", err)
+				return fmt.Errorf("could not store local id: %v", err)
 			}
 		}
 	}
 
 	if err := repos.Sync(tx).SetLastUpdate(newTS); err != nil {
-		return nil, fmt.Errorf("could not store update timestamp: %v", err)
+		return fmt.Errorf("could not store update timestamp: %v", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("could not sync items: %v", err)
-	}
+	return nil
+}
 
-	return SyncResult{}, nil
+type SyncResult struct {
+	Before []CommandResult
 }
 
-type SyncResult struct{}
+func (sr SyncResult) Render() string {
+	var res string
+	for _, b := range sr.Before {
+		res += b.Render() + "\n"
+	}
 
-func (sr SyncResult) Render() string { return "tasks synced" }
+	return res + "tasks synced"
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"go-mod.ewintr.nl/planner/item"
 	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/command/task"
 	"go-mod.ewintr.nl/planner/plan/storage"
 	"go-mod.ewintr.nl/planner/plan/storage/memory"
 	"go-mod.ewintr.nl/planner/sync/client"
@@ -220,3 +221,81 @@ func TestSyncReceive(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncBeforeAfterReceive(t *testing.T) {
+	t.Parallel()
+
+	syncClient := client.NewMemory()
+	mems := memory.New()
+	yesterday := item.Today().Add(-1)
+	// done elsewhere
+	if err := syncClient.Update([]item.Item{{ID: "a", Kind: item.KindTask, Deleted: true, Updated: time.Now()}}); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if err := mems.Task(nil).Store(item.Task{ID: "a", Date: yesterday, TaskBody: item.TaskBody{Title: "a"}}); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if err := mems.LocalID(nil).Store("a", 1); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	cmd, err := command.NewSyncArgs(task.Rollover{}).Parse([]string{"sync"}, nil)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if _, err := cmd.Do(mems, syncClient); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	actItems, err := syncClient.Updated([]item.Kind{item.KindTask}, time.Time{})
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if len(actItems) != 1 || !actItems[0].Deleted {
+		t.Errorf("exp deleted item, got %v", actItems)
+	}
+	actTasks, err := mems.Task(nil).FindMany(storage.TaskListParams{})
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if len(actTasks) != 0 {
+		t.Errorf("exp 0, got %v", len(actTasks))
+	}
+}
+
+// storeItem is a command that has an item sent on the next sync
+type storeItem struct {
+	it item.Item
+}
+
+func (si storeItem) Do(repos command.Repositories, _ client.Client) (command.CommandResult, error) {
+	return command.SyncResult{}, repos.Sync(nil).Store(si.it)
+}
+
+func TestSyncBefore(t *testing.T) {
+	t.Parallel()
+
+	syncClient := client.NewMemory()
+	mems := memory.New()
+	it := item.Item{
+		ID:   "a",
+		Kind: item.KindTask,
+		Body: `{"title":"title","duration":"0s"}`,
+	}
+
+	cmd, err := command.NewSyncArgs(storeItem{it: it}).Parse([]string{"sync"}, nil)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if _, err := cmd.Do(mems, syncClient); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	actItems, err := syncClient.Updated([]item.Kind{item.KindTask}, time.Time{})
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if len(actItems) != 1 || actItems[0].ID != it.ID {
+		t.Errorf("exp item %v, got %v", it.ID, actItems)
+	}
+}
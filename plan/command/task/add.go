@@ -3,6 +3,7 @@ package task
 import (
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +18,12 @@ import (
 type AddArgs struct {
 	fieldTPL map[string][]string
 	Task     item.Task
+	// Parsed lists the fields that were found in the title
+	Parsed []string
+	// ParentLocalID makes the task a subtask
+	ParentLocalID int
+	// BlockedBy holds the local ids of the tasks that must be done first
+	BlockedBy []string
 }
 
 func NewAddArgs() AddArgs {
@@ -27,16 +34,30 @@ func NewAddArgs() AddArgs {
 			"project":  {"p", "project"},
 			"duration": {"dur", "duration", "for"},
 			"recurrer": {"rec", "recurrer"},
+			"priority": {"prio", "priority"},
+			"tags":     {"tag", "tags"},
+			"parent":   {"parent"},
+			"blocked":  {"after", "blockedby"},
+			"deadline": {"due", "deadline"},
 		},
 	}
 }
 
+// Parse reads "add <title>" and the fields. Words in the title that start
+// with @, like @home, are tags, unless there is a tags field. A word that
+// starts with a backslash, as in \@home, is kept in the title without it.
 func (aa AddArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
 	if len(main) == 0 || !slices.Contains([]string{"add", "a", "new", "n"}, main[0]) {
 		return nil, command.ErrWrongCommand
 	}
 
-	main = main[1:]
+	return aa.parse(main[1:], fields, false)
+}
+
+// parse creates the task from the words of the title and the fields. Tags
+// are picked out of the title, with quick the other kinds of quickAdd are
+// too. A kind that is also given as a field stays in the title.
+func (aa AddArgs) parse(main []string, fields map[string]string, quick bool) (command.Command, error) {
 	if len(main) == 0 {
 		return nil, fmt.Errorf("%w: title is required for add", command.ErrInvalidArg)
 	}
@@ -45,10 +66,28 @@ func (aa AddArgs) Parse(main []string, fields map[string]string) (command.Comman
 		return nil, err
 	}
 
+	// explicit fields win over what is found in the title
+	skip := make([]string, 0, len(fields)+len(quickKinds))
+	for k := range fields {
+		skip = append(skip, k)
+	}
+	if !quick {
+		skip = append(skip, quickKinds...)
+	}
+	qa := parseQuickAdd(main, item.Today(), skip)
+	if qa.Title == "" {
+		return nil, fmt.Errorf("%w: title is required for add", command.ErrInvalidArg)
+	}
 	tsk := item.Task{
-		ID: uuid.New().String(),
+		ID:   uuid.New().String(),
+		Date: qa.Date,
 		TaskBody: item.TaskBody{
-			Title: strings.Join(main, " "),
+			Title:    qa.Title,
+			Project:  qa.Project,
+			Time:     qa.Time,
+			Duration: qa.Duration,
+			Created:  item.Today(),
+			Tags:     item.NewTags(qa.Tags...),
 		},
 	}
 
@@ -62,6 +101,13 @@ func (aa AddArgs) Parse(main []string, fields map[string]string) (command.Comman
 		}
 		tsk.Date = d
 	}
+	if val, ok := fields["deadline"]; ok {
+		d := item.NewDateFromString(val)
+		if d.IsZero() {
+			return nil, fmt.Errorf("%w: could not parse deadline", command.ErrInvalidArg)
+		}
+		tsk.Deadline = d
+	}
 	if val, ok := fields["time"]; ok {
 		t := item.NewTimeFromString(val)
 		if t.IsZero() {
@@ -76,18 +122,58 @@ func (aa AddArgs) Parse(main []string, fields map[string]string) (command.Comman
 		}
 		tsk.Duration = d
 	}
+	if val, ok := fields["priority"]; ok {
+		prio, err := item.ParsePriority(val)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", command.ErrInvalidArg, err)
+		}
+		tsk.Priority = prio
+	}
+	if val, ok := fields["tags"]; ok {
+		tsk.Tags = item.NewTags(splitTags(val)...)
+	}
 	if val, ok := fields["recurrer"]; ok {
-		rec := item.NewRecurrer(val)
+		rec := item.ParseRecurrer(val, item.Today())
 		if rec == nil {
 			return nil, fmt.Errorf("%w: could not parse recurrer", command.ErrInvalidArg)
 		}
 		tsk.Recurrer = rec
 		tsk.RecurNext = tsk.Recurrer.First()
 	}
+	if qa.Recurrer != "" {
+		// a recurrence in the title starts on the date, if there is one
+		ref := item.Today()
+		if !tsk.Date.IsZero() {
+			ref = tsk.Date
+		}
+		rec := item.ParseRecurrer(qa.Recurrer, ref)
+		if rec == nil {
+			return nil, fmt.Errorf("%w: could not parse recurrer", command.ErrInvalidArg)
+		}
+		tsk.Recurrer = rec
+		tsk.RecurNext = tsk.Recurrer.First()
+	}
+
+	var parentLID int
+	if val, ok := fields["parent"]; ok {
+		if parentLID, err = strconv.Atoi(val); err != nil {
+			return nil, fmt.Errorf("%w: parent is not a local id: %v", command.ErrInvalidArg, val)
+		}
+	}
+
+	var blockedBy []string
+	if val, ok := fields["blocked"]; ok {
+		if blockedBy, err = parseLocalRefs(val); err != nil {
+			return nil, err
+		}
+	}
 
 	return &Add{
 		Args: AddArgs{
-			Task: tsk,
+			Task:          tsk,
+			Parsed:        qa.Parsed,
+			ParentLocalID: parentLID,
+			BlockedBy:     blockedBy,
 		},
 	}, nil
 }
@@ -103,7 +189,31 @@ func (a Add) Do(repos command.Repositories, _ client.Client) (command.CommandRes
 	}
 	defer tx.Rollback()
 
-	if err := repos.Task(tx).Store(a.Args.Task); err != nil {
+	tsk := a.Args.Task
+	if a.Args.ParentLocalID != 0 {
+		parent, err := findByLocalRef(repos, tx, a.Args.ParentLocalID, 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkSubtask(tsk, parent); err != nil {
+			return nil, err
+		}
+		siblings, err := findSubtasks(repos.Task(tx), parent.ID)
+		if err != nil {
+			return nil, err
+		}
+		tsk.Parent = parent.ID
+		tsk.Position = 1
+		if len(siblings) > 0 {
+			tsk.Position = siblings[len(siblings)-1].Position + 1
+		}
+	}
+
+	if tsk.BlockedBy, err = resolveBlockers(repos, tx, a.Args.BlockedBy); err != nil {
+		return nil, err
+	}
+
+	if err := repos.Task(tx).Store(tsk); err != nil {
 		return nil, fmt.Errorf("could not store task: %v", err)
 	}
 
@@ -111,11 +221,11 @@ func (a Add) Do(repos command.Repositories, _ client.Client) (command.CommandRes
 	if err != nil {
 		return nil, fmt.Errorf("could not create next local id: %v", err)
 	}
-	if err := repos.LocalID(tx).Store(a.Args.Task.ID, localID); err != nil {
+	if err := repos.LocalID(tx).Store(tsk.ID, localID); err != nil {
 		return nil, fmt.Errorf("could not store local id: %v", err)
 	}
 
-	it, err := a.Args.Task.Item()
+	it, err := tsk.Item()
 	if err != nil {
 		return nil, fmt.Errorf("could not convert task to sync item: %v", err)
 	}
@@ -128,14 +238,48 @@ func (a Add) Do(repos command.Repositories, _ client.Client) (command.CommandRes
 	}
 
 	return AddResult{
-		LocalID: localID,
+		LocalID:       localID,
+		ParentLocalID: a.Args.ParentLocalID,
+		Task:          tsk,
+		Parsed:        a.Args.Parsed,
 	}, nil
 }
 
 type AddResult struct {
-	LocalID int
+	LocalID       int
+	ParentLocalID int
+	Task          item.Task
+	Parsed        []string
 }
 
 func (ar AddResult) Render() string {
-	return fmt.Sprintf("stored task %s", format.Bold(fmt.Sprintf("%d", ar.LocalID)))
+	stored := fmt.Sprintf("stored task %s", format.Bold(fmt.Sprintf("%d", ar.LocalID)))
+	if ar.ParentLocalID != 0 {
+		stored = fmt.Sprintf("stored subtask %s", format.Bold(formatLocalRef(ar.ParentLocalID, ar.Task.Position)))
+	}
+	if len(ar.Parsed) == 0 {
+		return stored
+	}
+
+	lines := []string{stored, fmt.Sprintf("  title: %s", ar.Task.Title)}
+	for _, p := range ar.Parsed {
+		var val string
+		switch p {
+		case "date":
+			val = ar.Task.Date.String()
+		case "time":
+			val = ar.Task.Time.String()
+		case "project":
+			val = ar.Task.Project
+		case "duration":
+			val = ar.Task.Duration.String()
+		case "recurrer":
+			val = ar.Task.Recurrer.String()
+		case "tags":
+			val = formatTags(ar.Task.Tags)
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", p, val))
+	}
+
+	return strings.Join(lines, "\n")
 }
@@ -1,6 +1,7 @@
 package task_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ func TestAdd(t *testing.T) {
 	aTime := item.NewTime(12, 0)
 	anHourStr := "1h"
 	anHour := time.Hour
+	weekly := item.NewRecurrer(fmt.Sprintf("%s, weekly, monday", item.Today().String()))
 
 	for _, tc := range []struct {
 		name    string
@@ -45,6 +47,7 @@ func TestAdd(t *testing.T) {
 				"date":     aDate.String(),
 				"time":     aTime.String(),
 				"duration": anHourStr,
+				"priority": "p2",
 			},
 			expTask: item.Task{
 				ID:   "title",
@@ -54,9 +57,94 @@ func TestAdd(t *testing.T) {
 					Project:  "project",
 					Time:     aTime,
 					Duration: anHour,
+					Priority: item.Priority2,
 				},
 			},
 		},
+		{
+			name: "deadline",
+			main: []string{"add", "title"},
+			fields: map[string]string{
+				"due": aDate.String(),
+			},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{
+					Title:    "title",
+					Deadline: aDate,
+				},
+			},
+		},
+		{
+			name: "invalid deadline",
+			main: []string{"add", "title"},
+			fields: map[string]string{
+				"due": "someday",
+			},
+			expErr: true,
+		},
+		{
+			name: "tags in title",
+			main: []string{"add", "call", "@phone", "bob", "@Home"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{
+					Title: "call bob",
+					Tags:  []string{"home", "phone"},
+				},
+			},
+		},
+		{
+			name: "escaped tag",
+			main: []string{"add", "mail", `\@home`},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{
+					Title: "mail @home",
+				},
+			},
+		},
+		{
+			name: "tags field wins",
+			main: []string{"add", "call", "@phone"},
+			fields: map[string]string{
+				"tags": "work",
+			},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{
+					Title: "call @phone",
+					Tags:  []string{"work"},
+				},
+			},
+		},
+		{
+			name: "invalid priority",
+			main: []string{"add", "title"},
+			fields: map[string]string{
+				"priority": "p5",
+			},
+			expErr: true,
+		},
+		{
+			name: "natural recurrer",
+			main: []string{"add", "title"},
+			fields: map[string]string{
+				"recurrer": "every monday",
+			},
+			expTask: item.Task{
+				ID:        "title",
+				Recurrer:  weekly,
+				RecurNext: weekly.First(),
+				TaskBody: item.TaskBody{
+					Title: "title",
+				},
+			},
+		},
+		{
+			name: "invalid recurrer",
+			main: []string{"add", "title"},
+			fields: map[string]string{
+				"recurrer": "every now and then",
+			},
+			expErr: true,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			// setup
@@ -100,6 +188,7 @@ func TestAdd(t *testing.T) {
 				t.Errorf("exp string not te be empty")
 			}
 			tc.expTask.ID = actTasks[0].ID
+			tc.expTask.Created = item.Today()
 			if diff := item.TaskDiff(tc.expTask, actTasks[0]); diff != "" {
 				t.Errorf("(exp -, got +)\n%s", diff)
 			}
@@ -0,0 +1,117 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/storage"
+)
+
+// parseLocalRefs checks a list of local ids and subtask references, like
+// 12,13.2, and returns them
+func parseLocalRefs(val string) ([]string, error) {
+	refs := splitTags(val)
+	for _, ref := range refs {
+		if _, _, err := parseLocalRef(ref); err != nil {
+			return nil, fmt.Errorf("%w: %v", command.ErrInvalidArg, err)
+		}
+	}
+
+	return refs, nil
+}
+
+// resolveBlockers finds the ids of the tasks that refs point to. It returns
+// nil when there are none.
+func resolveBlockers(repos command.Repositories, tx *storage.Tx, refs []string) ([]string, error) {
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		lid, sub, err := parseLocalRef(ref)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", command.ErrInvalidArg, err)
+		}
+		blocker, err := findByLocalRef(repos, tx, lid, sub)
+		if err != nil {
+			return nil, fmt.Errorf("could not find blocking task %s: %v", ref, err)
+		}
+		ids = append(ids, blocker.ID)
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return ids, nil
+}
+
+// checkCycle returns an error when blocking the task with id by the tasks
+// in blockerIDs would make it wait for itself
+func checkCycle(repo storage.Task, id string, blockerIDs []string) error {
+	seen := make(map[string]bool)
+	todo := slices.Clone(blockerIDs)
+	for len(todo) > 0 {
+		next := todo[0]
+		todo = todo[1:]
+		if next == id {
+			return fmt.Errorf("%w: this would make the task wait for itself", command.ErrInvalidArg)
+		}
+		if seen[next] {
+			continue
+		}
+		seen[next] = true
+
+		blocker, err := repo.FindOne(next)
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			continue
+		case err != nil:
+			return fmt.Errorf("could not get blocking task: %v", err)
+		}
+		todo = append(todo, blocker.BlockedBy...)
+	}
+
+	return nil
+}
+
+// openBlockers returns the tasks that block tsk and are not done yet. Done
+// tasks are deleted, so those are the ones that can still be found.
+func openBlockers(repo storage.Task, tsk item.Task) ([]item.Task, error) {
+	blockers := make([]item.Task, 0, len(tsk.BlockedBy))
+	for _, id := range tsk.BlockedBy {
+		blocker, err := repo.FindOne(id)
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("could not get blocking task: %v", err)
+		}
+		blockers = append(blockers, blocker)
+	}
+
+	return blockers, nil
+}
+
+// isBlocked tells whether tsk waits for another task, given all tasks that
+// are not done yet by id
+func isBlocked(tsk item.Task, open map[string]item.Task) bool {
+	for _, id := range tsk.BlockedBy {
+		if _, ok := open[id]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// byID indexes tasks by their id
+func byID(tasks []item.Task) map[string]item.Task {
+	idx := make(map[string]item.Task, len(tasks))
+	for _, tsk := range tasks {
+		idx[tsk.ID] = tsk
+	}
+
+	return idx
+}
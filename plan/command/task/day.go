@@ -0,0 +1,305 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/format"
+	"go-mod.ewintr.nl/planner/plan/storage"
+	"go-mod.ewintr.nl/planner/sync/client"
+)
+
+type DayArgs struct {
+	Date item.Date
+	// Start and End are the part of the day that is available for planned
+	// tasks
+	Start item.Time
+	End   item.Time
+}
+
+// NewDayArgs takes the working hours. Zero times mean nine to five.
+func NewDayArgs(start, end item.Time) DayArgs {
+	if start.IsZero() {
+		start = item.NewTime(9, 0)
+	}
+	if end.IsZero() {
+		end = item.NewTime(17, 0)
+	}
+
+	return DayArgs{
+		Start: start,
+		End:   end,
+	}
+}
+
+// Parse reads "day", optionally followed by the date to plan, like
+// "day tomorrow" or "day 2024-12-06"
+func (da DayArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
+	if len(main) == 0 || main[0] != "day" {
+		return nil, command.ErrWrongCommand
+	}
+
+	date := item.Today()
+	if len(main) > 1 {
+		date = item.ParseDate(strings.Join(main[1:], " "), date)
+		if date.IsZero() {
+			return nil, fmt.Errorf("%w: could not parse date", command.ErrInvalidArg)
+		}
+	}
+
+	return Day{
+		Args: DayArgs{
+			Date:  date,
+			Start: da.Start,
+			End:   da.End,
+		},
+	}, nil
+}
+
+// Day shows the agenda of a single day. Tasks with a time are placed on the
+// hours they take up, the others are listed below it.
+type Day struct {
+	Args DayArgs
+}
+
+func (d Day) Do(repos command.Repositories, _ client.Client) (command.CommandResult, error) {
+	tx, err := repos.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	localIDs, err := repos.LocalID(tx).FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not get local ids: %v", err)
+	}
+	// a time in another zone can fall on the day before or after
+	all, err := repos.Task(tx).FindMany(storage.TaskListParams{
+		HasDate: true,
+		From:    d.Args.Date.Add(-1),
+		To:      d.Args.Date.Add(1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := repos.Schedule(tx).Find(d.Args.Date, d.Args.Date)
+	if err != nil {
+		return nil, fmt.Errorf("could not find schedules: %v", err)
+	}
+
+	tasks := make([]TaskWithLID, 0, len(all))
+	for _, tsk := range all {
+		if tsk.Recurrer != nil || tsk.Hidden(d.Args.Date) {
+			continue
+		}
+		lid, ok := localIDs[tsk.ID]
+		if !ok {
+			return nil, fmt.Errorf("could not find local id for %s", tsk.ID)
+		}
+		tasks = append(tasks, TaskWithLID{
+			LocalID:       lid,
+			ParentLocalID: localIDs[tsk.Parent],
+			Task:          tsk,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not plan day: %v", err)
+	}
+
+	return NewDayResult(d.Args.Date, tasks, schedules, d.Args.Start, d.Args.End), nil
+}
+
+// Block is the part of the day a timed task takes up
+type Block struct {
+	Task  TaskWithLID
+	Start time.Time
+	End   time.Time
+}
+
+func (b Block) overlaps(o Block) bool {
+	if b.Start.Equal(o.Start) {
+		return true
+	}
+
+	return b.Start.Before(o.End) && o.Start.Before(b.End)
+}
+
+func (b Block) span() string {
+	return fmt.Sprintf("%s-%s", b.Start.Format(item.TimeFormat), b.End.Format(item.TimeFormat))
+}
+
+// Overlap is a pair of blocks that are planned at the same time
+type Overlap struct {
+	First  Block
+	Second Block
+}
+
+type DayResult struct {
+	Date      item.Date
+	Blocks    []Block
+	Untimed   []TaskWithLID
+	Schedules []item.Schedule
+	Overlaps  []Overlap
+	Planned   time.Duration
+	Available time.Duration
+	// the hours shown in the agenda
+	FirstHour int
+	LastHour  int
+}
+
+// NewDayResult places the timed tasks on the day, in the local timezone, and
+// finds the ones that overlap. Tasks can include those of the days around
+// date: a timed task is placed when it starts on date in the local timezone,
+// one without a time when it is planned on date. The planned time counts the
+// duration of all tasks on the day, also those without a time.
+func NewDayResult(date item.Date, tasks []TaskWithLID, schedules []item.Schedule, start, end item.Time) DayResult {
+	dayStart := start.On(date).In(item.Timezone())
+	dayEnd := end.On(date).In(item.Timezone())
+	zero := item.NewTime(0, 0)
+	midnight := zero.On(date.Add(1))
+	dr := DayResult{
+		Date:      date,
+		Schedules: schedules,
+		Available: dayEnd.Sub(dayStart),
+		FirstHour: dayStart.Hour(),
+		LastHour:  dayEnd.Hour(),
+	}
+	if dayEnd.Minute() == 0 && dr.LastHour > dr.FirstHour {
+		dr.LastHour--
+	}
+
+	for _, tl := range tasks {
+		if tl.Task.Time.IsZero() {
+			if !tl.Task.Date.Equal(date) {
+				continue
+			}
+			dr.Planned += tl.Task.Duration
+			dr.Untimed = append(dr.Untimed, tl)
+			continue
+		}
+		bStart := tl.Task.Time.On(tl.Task.Date).In(item.Timezone())
+		if year, month, day := bStart.Date(); !item.NewDate(year, int(month), day).Equal(date) {
+			continue
+		}
+		dr.Planned += tl.Task.Duration
+		bEnd := bStart.Add(tl.Task.Duration)
+		if bEnd.After(midnight) {
+			bEnd = midnight
+		}
+		dr.Blocks = append(dr.Blocks, Block{
+			Task:  tl,
+			Start: bStart,
+			End:   bEnd,
+		})
+		dr.FirstHour = min(dr.FirstHour, bStart.Hour())
+		lastHour := bStart.Hour()
+		if bEnd.After(bStart) {
+			lastHour = bEnd.Add(-time.Minute).Hour()
+		}
+		dr.LastHour = max(dr.LastHour, lastHour)
+	}
+	sort.Slice(dr.Blocks, func(i, j int) bool {
+		if !dr.Blocks[i].Start.Equal(dr.Blocks[j].Start) {
+			return dr.Blocks[i].Start.Before(dr.Blocks[j].Start)
+		}
+		return dr.Blocks[i].Task.LocalID < dr.Blocks[j].Task.LocalID
+	})
+	sort.Slice(dr.Untimed, func(i, j int) bool {
+		return dr.Untimed[i].LocalID < dr.Untimed[j].LocalID
+	})
+
+	for i := range dr.Blocks {
+		for j := i + 1; j < len(dr.Blocks); j++ {
+			if dr.Blocks[i].overlaps(dr.Blocks[j]) {
+				dr.Overlaps = append(dr.Overlaps, Overlap{First: dr.Blocks[i], Second: dr.Blocks[j]})
+			}
+		}
+	}
+
+	return dr
+}
+
+func (dr DayResult) Render() string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "\n%s %s\n", format.Bold(dr.Date.String()), strings.ToLower(dr.Date.Time().Weekday().String()))
+
+	if len(dr.Schedules) > 0 {
+		data := [][]string{{"all day"}}
+		for _, sch := range dr.Schedules {
+			data = append(data, []string{sch.Title})
+		}
+		fmt.Fprintf(&out, "\n%s\n", format.Table(data))
+	}
+
+	data := [][]string{{"hour", "id", "time", "title"}}
+	for h := dr.FirstHour; h <= dr.LastHour; h++ {
+		hourStr := fmt.Sprintf("%02d:00", h)
+		active := 0
+		for _, b := range dr.Blocks {
+			if !b.inHour(h) {
+				continue
+			}
+			timeStr := b.span()
+			if b.Start.Hour() != h {
+				timeStr = "..."
+			}
+			row := []string{"", b.Task.localRef(), timeStr, b.Task.Task.Title}
+			if active == 0 {
+				row[0] = hourStr
+			}
+			data = append(data, row)
+			active++
+		}
+		if active == 0 {
+			data = append(data, []string{hourStr, "", "", ""})
+		}
+	}
+	fmt.Fprintf(&out, "\n%s\n", format.Table(data))
+
+	if len(dr.Untimed) > 0 {
+		data := [][]string{{"id", "dur", "title"}}
+		for _, tl := range dr.Untimed {
+			durStr := ""
+			if tl.Task.Duration > 0 {
+				durStr = tl.Task.Duration.String()
+			}
+			data = append(data, []string{tl.localRef(), durStr, tl.Task.Title})
+		}
+		fmt.Fprintf(&out, "\nno time:\n\n%s\n", format.Table(data))
+	}
+
+	if len(dr.Overlaps) > 0 {
+		out.WriteString("\noverlaps:\n\n")
+		for _, o := range dr.Overlaps {
+			fmt.Fprintf(&out, "  %s %s (%s) and %s %s (%s)\n",
+				o.First.Task.localRef(), o.First.Task.Task.Title, o.First.span(),
+				o.Second.Task.localRef(), o.Second.Task.Task.Title, o.Second.span(),
+			)
+		}
+	}
+
+	fmt.Fprintf(&out, "\nplanned %s of %s available", format.Bold(dr.Planned.String()), dr.Available.String())
+	if dr.Planned > dr.Available {
+		fmt.Fprintf(&out, ", %s too much", format.Bold((dr.Planned - dr.Available).String()))
+	}
+	out.WriteString("\n")
+
+	return out.String()
+}
+
+// inHour tells whether the block takes up part of hour h. A block without a
+// duration is only in the hour it starts.
+func (b Block) inHour(h int) bool {
+	if !b.End.After(b.Start) {
+		return b.Start.Hour() == h
+	}
+	hStart := time.Date(b.Start.Year(), b.Start.Month(), b.Start.Day(), h, 0, 0, 0, b.Start.Location())
+	hEnd := hStart.Add(time.Hour)
+
+	return b.Start.Before(hEnd) && hStart.Before(b.End)
+}
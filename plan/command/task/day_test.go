@@ -0,0 +1,205 @@
+package task_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command/task"
+	"go-mod.ewintr.nl/planner/plan/storage/memory"
+)
+
+func TestDayParse(t *testing.T) {
+	t.Parallel()
+
+	today := item.Today()
+	for _, tc := range []struct {
+		name    string
+		main    []string
+		expDate item.Date
+		expErr  bool
+	}{
+		{
+			name:    "today",
+			main:    []string{"day"},
+			expDate: today,
+		},
+		{
+			name:    "tomorrow",
+			main:    []string{"day", "tomorrow"},
+			expDate: today.Add(1),
+		},
+		{
+			name:    "date",
+			main:    []string{"day", "2024-12-06"},
+			expDate: item.NewDate(2024, 12, 6),
+		},
+		{
+			name:   "invalid",
+			main:   []string{"day", "some", "time"},
+			expErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, actErr := task.NewDayArgs(item.Time{}, item.NewTime(18, 0)).Parse(tc.main, nil)
+			if tc.expErr != (actErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expErr, actErr)
+			}
+			if tc.expErr {
+				return
+			}
+			dayCmd, ok := cmd.(task.Day)
+			if !ok {
+				t.Errorf("exp true, got false")
+			}
+			if !dayCmd.Args.Date.Equal(tc.expDate) {
+				t.Errorf("exp %v, got %v", tc.expDate, dayCmd.Args.Date)
+			}
+			if dayCmd.Args.Start != item.NewTime(9, 0) || dayCmd.Args.End != item.NewTime(18, 0) {
+				t.Errorf("exp 09:00-18:00, got %v-%v", dayCmd.Args.Start, dayCmd.Args.End)
+			}
+		})
+	}
+}
+
+func TestDay(t *testing.T) {
+	t.Parallel()
+
+	date := item.NewDate(2024, 12, 6)
+	mems := memory.New()
+	for i, tsk := range []item.Task{
+		{ID: "a", Date: date, TaskBody: item.TaskBody{Title: "a", Time: item.NewTime(9, 30), Duration: time.Hour}},
+		{ID: "b", Date: date, TaskBody: item.TaskBody{Title: "b", Time: item.NewTime(10, 0), Duration: 30 * time.Minute}},
+		{ID: "c", Date: date, TaskBody: item.TaskBody{Title: "c", Time: item.NewTime(11, 0), Duration: time.Hour}},
+		{ID: "d", Date: date, TaskBody: item.TaskBody{Title: "d", Duration: 2 * time.Hour}},
+		{ID: "e", Date: date.Add(1), TaskBody: item.TaskBody{Title: "e", Time: item.NewTime(9, 0)}},
+		{ID: "f", Date: date, TaskBody: item.TaskBody{Title: "f", SnoozeUntil: date.Add(1)}},
+	} {
+		if err := mems.Task(nil).Store(tsk); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+	}
+	if err := mems.Schedule(nil).Store(item.Schedule{ID: "s", Date: date, ScheduleBody: item.ScheduleBody{Title: "holiday"}}); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	args := task.NewDayArgs(item.NewTime(8, 0), item.NewTime(18, 0))
+	args.Date = date
+	res, err := task.Day{Args: args}.Do(mems, nil)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	dayRes, ok := res.(task.DayResult)
+	if !ok {
+		t.Errorf("exp true, got false")
+	}
+
+	blocks := make([]string, 0)
+	for _, b := range dayRes.Blocks {
+		blocks = append(blocks, b.Task.Task.Title)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c"}, blocks); diff != "" {
+		t.Errorf("(exp -, got +)\n%s", diff)
+	}
+	untimed := make([]string, 0)
+	for _, tl := range dayRes.Untimed {
+		untimed = append(untimed, tl.Task.Title)
+	}
+	if diff := cmp.Diff([]string{"d"}, untimed); diff != "" {
+		t.Errorf("(exp -, got +)\n%s", diff)
+	}
+	if len(dayRes.Schedules) != 1 {
+		t.Errorf("exp 1, got %v", len(dayRes.Schedules))
+	}
+	if len(dayRes.Overlaps) != 1 {
+		t.Errorf("exp 1, got %v", len(dayRes.Overlaps))
+	}
+	if dayRes.Overlaps[0].First.Task.Task.Title != "a" || dayRes.Overlaps[0].Second.Task.Task.Title != "b" {
+		t.Errorf("exp a and b, got %v and %v", dayRes.Overlaps[0].First.Task.Task.Title, dayRes.Overlaps[0].Second.Task.Task.Title)
+	}
+	if dayRes.Planned != 4*time.Hour+30*time.Minute {
+		t.Errorf("exp 4h30m, got %v", dayRes.Planned)
+	}
+	if dayRes.Available != 10*time.Hour {
+		t.Errorf("exp 10h, got %v", dayRes.Available)
+	}
+	if dayRes.FirstHour != 8 || dayRes.LastHour != 17 {
+		t.Errorf("exp 8-17, got %v-%v", dayRes.FirstHour, dayRes.LastHour)
+	}
+	if out := dayRes.Render(); !strings.Contains(out, "holiday") || !strings.Contains(out, "overlaps") {
+		t.Errorf("exp schedule and overlaps in output, got %v", out)
+	}
+}
+
+func TestDayZones(t *testing.T) {
+	t.Parallel()
+
+	date := item.NewDate(2024, 12, 6)
+	// zones that are two hours behind and ahead of the local one
+	midnight := item.NewTime(0, 0)
+	_, offset := midnight.On(date.Add(1)).Zone()
+	behind := time.FixedZone("behind", offset-2*3600)
+	ahead := time.FixedZone("ahead", offset+2*3600)
+
+	mems := memory.New()
+	for i, tsk := range []item.Task{
+		{ID: "a", Date: date, TaskBody: item.TaskBody{Title: "local", Time: item.NewTime(10, 0)}},
+		{ID: "b", Date: date, TaskBody: item.TaskBody{Title: "next local day", Time: item.NewTimeIn(23, 0, behind)}},
+		{ID: "c", Date: date.Add(1), TaskBody: item.TaskBody{Title: "previous zoned day", Time: item.NewTimeIn(1, 0, ahead)}},
+		{ID: "d", Date: date.Add(1), TaskBody: item.TaskBody{Title: "untimed next day"}},
+	} {
+		if err := mems.Task(nil).Store(tsk); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+	}
+
+	args := task.NewDayArgs(item.Time{}, item.Time{})
+	args.Date = date
+	res, err := task.Day{Args: args}.Do(mems, nil)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	dayRes := res.(task.DayResult)
+	act := make(map[string]int)
+	for _, b := range dayRes.Blocks {
+		act[b.Task.Task.Title] = b.Start.Hour()
+	}
+	if diff := cmp.Diff(map[string]int{"local": 10, "previous zoned day": 23}, act); diff != "" {
+		t.Errorf("(exp -, got +)\n%s", diff)
+	}
+	if len(dayRes.Untimed) != 0 {
+		t.Errorf("exp 0, got %v", len(dayRes.Untimed))
+	}
+}
+
+func TestDayResultHours(t *testing.T) {
+	t.Parallel()
+
+	date := item.NewDate(2024, 12, 6)
+	tasks := []task.TaskWithLID{
+		{LocalID: 1, Task: item.Task{ID: "a", Date: date, TaskBody: item.TaskBody{Title: "a", Time: item.NewTime(7, 15), Duration: 30 * time.Minute}}},
+		{LocalID: 2, Task: item.Task{ID: "b", Date: date, TaskBody: item.TaskBody{Title: "b", Time: item.NewTime(23, 0), Duration: 2 * time.Hour}}},
+		{LocalID: 3, Task: item.Task{ID: "c", Date: date, TaskBody: item.TaskBody{Title: "c", Time: item.NewTime(7, 15)}}},
+	}
+	dr := task.NewDayResult(date, tasks, nil, item.NewTime(9, 0), item.NewTime(17, 30))
+	if dr.FirstHour != 7 || dr.LastHour != 23 {
+		t.Errorf("exp 7-23, got %v-%v", dr.FirstHour, dr.LastHour)
+	}
+	if dr.Available != 8*time.Hour+30*time.Minute {
+		t.Errorf("exp 8h30m, got %v", dr.Available)
+	}
+	if len(dr.Overlaps) != 1 {
+		t.Errorf("exp 1, got %v", len(dr.Overlaps))
+	}
+	if dr.Blocks[2].End.Hour() != 0 {
+		t.Errorf("exp block to end at midnight, got %v", dr.Blocks[2].End)
+	}
+}
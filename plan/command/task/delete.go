@@ -3,19 +3,31 @@ package task
 import (
 	"fmt"
 	"slices"
-	"strconv"
 
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/cli/arg"
 	"go-mod.ewintr.nl/planner/plan/command"
 	"go-mod.ewintr.nl/planner/plan/format"
+	"go-mod.ewintr.nl/planner/plan/storage"
 	"go-mod.ewintr.nl/planner/sync/client"
 )
 
 type DeleteArgs struct {
-	LocalID int
+	fieldTPL map[string][]string
+	LocalID  int
+	Sub      int
+	Scope    Scope
+	// Cascade removes the subtasks too
+	Cascade bool
 }
 
 func NewDeleteArgs() DeleteArgs {
-	return DeleteArgs{}
+	return DeleteArgs{
+		fieldTPL: map[string][]string{
+			"scope":   {"scope"},
+			"cascade": {"cascade"},
+		},
+	}
 }
 
 func (da DeleteArgs) Parse(main []string, flags map[string]string) (command.Command, error) {
@@ -33,14 +45,38 @@ func (da DeleteArgs) Parse(main []string, flags map[string]string) (command.Comm
 		return nil, command.ErrWrongCommand
 	}
 
-	localID, err := strconv.Atoi(localIDStr)
+	localID, sub, err := parseLocalRef(localIDStr)
+	if err != nil {
+		return nil, err
+	}
+	flags, err = arg.ResolveFields(flags, da.fieldTPL)
 	if err != nil {
-		return nil, fmt.Errorf("not a local id: %v", main[1])
+		return nil, err
+	}
+	scope := ScopeThis
+	if val, ok := flags["scope"]; ok {
+		if scope, err = ParseScope(val); err != nil {
+			return nil, err
+		}
+	}
+
+	var cascade bool
+	if val, ok := flags["cascade"]; ok {
+		switch val {
+		case "yes":
+			cascade = true
+		case "no":
+		default:
+			return nil, fmt.Errorf("%w: cascade must be yes or no", command.ErrInvalidArg)
+		}
 	}
 
 	return &Delete{
 		Args: DeleteArgs{
 			LocalID: localID,
+			Sub:     sub,
+			Scope:   scope,
+			Cascade: cascade,
 		},
 	}, nil
 }
@@ -56,55 +92,121 @@ func (del Delete) Do(repos command.Repositories, _ client.Client) (command.Comma
 	}
 	defer tx.Rollback()
 
-	var id string
-	idMap, err := repos.LocalID(tx).FindAll()
+	tsk, err := findByLocalRef(repos, tx, del.Args.LocalID, del.Args.Sub)
 	if err != nil {
-		return nil, fmt.Errorf("could not get local ids: %v", err)
+		return nil, err
 	}
-	for tskID, lid := range idMap {
-		if del.Args.LocalID == lid {
-			id = tskID
+	subtasks, err := findSubtasks(repos.Task(tx), tsk.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	title := tsk.Title
+	var instances int
+	switch {
+	case len(subtasks) > 0 && !del.Args.Cascade:
+		return nil, fmt.Errorf("task has %d open subtasks, finish those first or use cascade:yes to remove them too", len(subtasks))
+	case len(subtasks) > 0:
+		for _, st := range subtasks {
+			if err := del.deleteTask(repos, tx, st); err != nil {
+				return nil, err
+			}
 		}
+		err = del.deleteTask(repos, tx, tsk)
+	case del.Args.Scope == ScopeThis:
+		err = del.deleteTask(repos, tx, tsk)
+	default:
+		title, instances, err = del.deleteSeries(repos, tx, tsk)
 	}
-	if id == "" {
-		return nil, fmt.Errorf("could not find local id")
+	if err != nil {
+		return nil, err
 	}
 
-	tsk, err := repos.Task(tx).FindOne(id)
-	if err != nil {
-		return nil, fmt.Errorf("could not get task: %v", err)
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not delete task: %v", err)
 	}
 
+	return DeleteResult{
+		Title:     title,
+		Instances: instances,
+		Subtasks:  len(subtasks),
+	}, nil
+}
+
+func (del Delete) deleteTask(repos command.Repositories, tx *storage.Tx, tsk item.Task) error {
 	it, err := tsk.Item()
 	if err != nil {
-		return nil, fmt.Errorf("could not convert task to sync item: %v", err)
+		return fmt.Errorf("could not convert task to sync item: %v", err)
 	}
 	it.Deleted = true
 	if err := repos.Sync(tx).Store(it); err != nil {
-		return nil, fmt.Errorf("could not store sync item: %v", err)
+		return fmt.Errorf("could not store sync item: %v", err)
 	}
 
-	if err := repos.LocalID(tx).Delete(id); err != nil {
-		return nil, fmt.Errorf("could not delete local id: %v", err)
+	if err := repos.LocalID(tx).Delete(tsk.ID); err != nil {
+		return fmt.Errorf("could not delete local id: %v", err)
 	}
 
-	if err := repos.Task(tx).Delete(id); err != nil {
-		return nil, fmt.Errorf("could not delete task: %v", err)
+	if err := repos.Task(tx).Delete(tsk.ID); err != nil {
+		return fmt.Errorf("could not delete task: %v", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("could not delete task: %v", err)
+	return nil
+}
+
+// deleteSeries deletes the recurring task, so no new instances are spawned,
+// together with the local instances that fall within the scope. The server
+// deletes the instances it already spawned in that range.
+func (del Delete) deleteSeries(repos command.Repositories, tx *storage.Tx, tsk item.Task) (string, int, error) {
+	parent, err := findSeries(repos.Task(tx), tsk)
+	if err != nil {
+		return "", 0, err
 	}
+	from := seriesFrom(del.Args.Scope, tsk, parent)
 
-	return DeleteResult{
-		Title: tsk.Title,
-	}, nil
+	it, err := parent.Item()
+	if err != nil {
+		return "", 0, fmt.Errorf("could not convert task to sync item: %v", err)
+	}
+	it.Deleted = true
+	it.RewriteFrom = from
+	if err := repos.Sync(tx).Store(it); err != nil {
+		return "", 0, fmt.Errorf("could not store sync item: %v", err)
+	}
+	if err := deleteLocal(repos, tx, parent.ID); err != nil {
+		return "", 0, err
+	}
+
+	instances, err := repos.Task(tx).FindMany(storage.TaskListParams{RecurParent: parent.ID})
+	if err != nil {
+		return "", 0, fmt.Errorf("could not get instances: %v", err)
+	}
+	var count int
+	for _, inst := range instances {
+		if from.After(inst.Date) {
+			continue
+		}
+		if err := deleteLocal(repos, tx, inst.ID); err != nil {
+			return "", 0, err
+		}
+		count++
+	}
+
+	return parent.Title, count, nil
 }
 
 type DeleteResult struct {
-	Title string
+	Title     string
+	Instances int
+	Subtasks  int
 }
 
 func (dr DeleteResult) Render() string {
+	if dr.Subtasks > 0 {
+		return fmt.Sprintf("removed task %s and %d subtasks", format.Bold(dr.Title), dr.Subtasks)
+	}
+	if dr.Instances > 0 {
+		return fmt.Sprintf("removed task %s and %d instances", format.Bold(dr.Title), dr.Instances)
+	}
 	return fmt.Sprintf("removed task %s", format.Bold(dr.Title))
 }
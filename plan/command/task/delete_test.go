@@ -2,8 +2,10 @@ package task_test
 
 import (
 	"errors"
+	"sort"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"go-mod.ewintr.nl/planner/item"
 	"go-mod.ewintr.nl/planner/plan/command/task"
 	"go-mod.ewintr.nl/planner/plan/storage"
@@ -46,6 +48,12 @@ func TestDelete(t *testing.T) {
 			name: "done",
 			main: []string{"done", "1"},
 		},
+		{
+			name:        "invalid cascade",
+			main:        []string{"done", "1"},
+			flags:       map[string]string{"cascade": "maybe"},
+			expParseErr: true,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			// setup
@@ -98,3 +106,119 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteSeries(t *testing.T) {
+	t.Parallel()
+
+	parent := item.Task{
+		ID:        "parent",
+		Recurrer:  item.NewRecurrer("2024-10-01, daily"),
+		RecurNext: item.NewDate(2024, 10, 8),
+		TaskBody:  item.TaskBody{Title: "series"},
+	}
+	instances := []item.Task{
+		{ID: "a", Date: item.NewDate(2024, 10, 5), RecurParent: parent.ID, TaskBody: item.TaskBody{Title: "series"}},
+		{ID: "b", Date: item.NewDate(2024, 10, 6), RecurParent: parent.ID, TaskBody: item.TaskBody{Title: "series"}},
+		{ID: "c", Date: item.NewDate(2024, 10, 7), RecurParent: parent.ID, TaskBody: item.TaskBody{Title: "series"}},
+	}
+
+	for _, tc := range []struct {
+		name           string
+		flags          map[string]string
+		expParseErr    bool
+		expIDs         []string
+		expSyncID      string
+		expRewriteFrom item.Date
+	}{
+		{
+			name:        "invalid scope",
+			flags:       map[string]string{"scope": "some"},
+			expParseErr: true,
+		},
+		{
+			name:      "this instance",
+			flags:     map[string]string{"scope": "this"},
+			expIDs:    []string{"a", "c", "parent"},
+			expSyncID: "b",
+		},
+		{
+			name:           "this and future",
+			flags:          map[string]string{"scope": "future"},
+			expIDs:         []string{"a"},
+			expSyncID:      "parent",
+			expRewriteFrom: item.NewDate(2024, 10, 6),
+		},
+		{
+			name:           "entire series",
+			flags:          map[string]string{"scope": "all"},
+			expIDs:         []string{},
+			expSyncID:      "parent",
+			expRewriteFrom: item.NewDate(2024, 10, 1),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			mems := memory.New()
+			for i, tsk := range append([]item.Task{parent}, instances...) {
+				if err := mems.Task(nil).Store(tsk); err != nil {
+					t.Errorf("exp nil, got %v", err)
+				}
+				if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+					t.Errorf("exp nil, got %v", err)
+				}
+			}
+
+			// parse
+			cmd, actParseErr := task.NewDeleteArgs().Parse([]string{"3", "done"}, tc.flags)
+			if tc.expParseErr != (actParseErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expParseErr, actParseErr)
+			}
+			if tc.expParseErr {
+				return
+			}
+
+			// do
+			if _, err := cmd.Do(mems, nil); err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+
+			// check
+			actTasks, err := mems.Task(nil).FindMany(storage.TaskListParams{})
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			actIDs := make([]string, 0)
+			for _, tsk := range actTasks {
+				actIDs = append(actIDs, tsk.ID)
+			}
+			sort.Strings(actIDs)
+			if diff := cmp.Diff(tc.expIDs, actIDs); diff != "" {
+				t.Errorf("(exp +, got -)\n%s", diff)
+			}
+			lids, err := mems.LocalID(nil).FindAll()
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if len(lids) != len(tc.expIDs) {
+				t.Errorf("exp %v, got %v", len(tc.expIDs), len(lids))
+			}
+			updated, err := mems.Sync(nil).FindAll()
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if len(updated) != 1 {
+				t.Errorf("exp 1, got %v", len(updated))
+				return
+			}
+			if updated[0].ID != tc.expSyncID {
+				t.Errorf("exp %v, got %v", tc.expSyncID, updated[0].ID)
+			}
+			if !updated[0].Deleted {
+				t.Errorf("exp true, got false")
+			}
+			if !tc.expRewriteFrom.Equal(updated[0].RewriteFrom) {
+				t.Errorf("exp %v, got %v", tc.expRewriteFrom, updated[0].RewriteFrom)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/format"
+	"go-mod.ewintr.nl/planner/sync/client"
+)
+
+const editHelp = `# The first line is the title, followed by one field per line. Leave a
+# field empty to clear it. Everything after the first empty line is notes.
+# Lines starting with # at the top are ignored.
+`
+
+// editFields are the fields that are written to the text, in this order
+var editFields = []string{"project", "date", "deadline", "time", "duration", "priority", "tags", "recurrer"}
+
+// EditFunc lets the user change text and returns the result
+type EditFunc func(text string) (string, error)
+
+type EditArgs struct {
+	LocalID int
+	Sub     int
+	Editor  EditFunc
+}
+
+func NewEditArgs() EditArgs {
+	return EditArgs{}
+}
+
+func (ea EditArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
+	if len(main) != 2 {
+		return nil, command.ErrWrongCommand
+	}
+	var localIDStr string
+	switch {
+	case main[0] == "edit":
+		localIDStr = main[1]
+	case main[1] == "edit":
+		localIDStr = main[0]
+	default:
+		return nil, command.ErrWrongCommand
+	}
+	localID, sub, err := parseLocalRef(localIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Edit{
+		Args: EditArgs{
+			LocalID: localID,
+			Sub:     sub,
+			Editor:  editInEditor,
+		},
+	}, nil
+}
+
+type Edit struct {
+	Args EditArgs
+}
+
+// Do lets the user edit a text version of the task. The changes are made
+// through an update, so they are checked the same way.
+func (e Edit) Do(repos command.Repositories, c client.Client) (command.CommandResult, error) {
+	tx, err := repos.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	tsk, err := findByLocalRef(repos, tx, e.Args.LocalID, e.Args.Sub)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not edit task: %v", err)
+	}
+
+	orig := taskText(tsk)
+	edited, err := e.Args.Editor(orig)
+	if err != nil {
+		return nil, fmt.Errorf("could not edit task: %v", err)
+	}
+	origTitle, origFields, origNotes, err := parseTaskText(orig)
+	if err != nil {
+		return nil, err
+	}
+	title, fields, notes, err := parseTaskText(edited)
+	if err != nil {
+		return nil, err
+	}
+
+	main := []string{"update", formatLocalRef(e.Args.LocalID, e.Args.Sub)}
+	if title != origTitle {
+		main = append(main, title)
+	}
+	changed := make(map[string]string)
+	for k, v := range fields {
+		if ov, ok := origFields[k]; !ok || ov != v {
+			changed[k] = v
+		}
+	}
+	for k := range origFields {
+		if _, ok := fields[k]; !ok {
+			changed[k] = ""
+		}
+	}
+	if notes != origNotes {
+		changed["notes"] = notes
+	}
+	if len(main) == 2 && len(changed) == 0 {
+		return EditResult{Title: tsk.Title}, nil
+	}
+
+	update, err := NewUpdateArgs().Parse(main, changed)
+	if err != nil {
+		return nil, err
+	}
+
+	return update.Do(repos, c)
+}
+
+type EditResult struct {
+	Title string
+}
+
+func (er EditResult) Render() string {
+	return fmt.Sprintf("nothing changed in task %s", format.Bold(er.Title))
+}
+
+// taskText shows the task in the format that parseTaskText reads
+func taskText(tsk item.Task) string {
+	vals := map[string]string{
+		"project":  tsk.Project,
+		"priority": tsk.Priority.String(),
+		"tags":     formatTags(tsk.Tags),
+	}
+	if !tsk.Date.IsZero() {
+		vals["date"] = tsk.Date.String()
+	}
+	if !tsk.Deadline.IsZero() {
+		vals["deadline"] = tsk.Deadline.String()
+	}
+	if !tsk.Time.IsZero() {
+		vals["time"] = tsk.Time.String()
+	}
+	if tsk.Duration > 0 {
+		vals["duration"] = tsk.Duration.String()
+	}
+	if tsk.Recurrer != nil {
+		vals["recurrer"] = tsk.Recurrer.String()
+	}
+
+	var b strings.Builder
+	b.WriteString(editHelp)
+	b.WriteString(tsk.Title + "\n")
+	for _, f := range editFields {
+		// instances follow the recurrer of their series
+		if f == "recurrer" && tsk.RecurParent != "" {
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s\n", f, vals[f])
+	}
+	b.WriteString("\n")
+	if tsk.Notes != "" {
+		b.WriteString(tsk.Notes + "\n")
+	}
+
+	return b.String()
+}
+
+// parseTaskText reads the title, the fields and the notes from text as it
+// is written by taskText
+func parseTaskText(text string) (string, map[string]string, string, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.HasPrefix(lines[0], "#") {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) == "" {
+		return "", nil, "", fmt.Errorf("%w: title can not be empty", command.ErrInvalidArg)
+	}
+	title := strings.TrimSpace(lines[0])
+
+	fields := make(map[string]string)
+	i := 1
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			break
+		}
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			return "", nil, "", fmt.Errorf("%w: not a field: %q", command.ErrInvalidArg, line)
+		}
+		key = strings.TrimSpace(key)
+		if _, ok := fields[key]; ok {
+			return "", nil, "", fmt.Errorf("%w: field %s is given twice", command.ErrInvalidArg, key)
+		}
+		fields[key] = strings.TrimSpace(val)
+	}
+	var notes string
+	if i < len(lines) {
+		notes = strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+	}
+
+	return title, fields, notes, nil
+}
+
+// summarizeNotes shortens notes to their first line
+func summarizeNotes(notes string) string {
+	first, rest, _ := strings.Cut(notes, "\n")
+	if rest != "" {
+		return first + " ..."
+	}
+
+	return first
+}
+
+// editInEditor lets the user edit text in $EDITOR, or vi if it is not set
+func editInEditor(text string) (string, error) {
+	f, err := os.CreateTemp("", "plan-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+	cmd := exec.Command(editor[0], append(editor[1:], f.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %v", editor[0], err)
+	}
+
+	res, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
@@ -0,0 +1,193 @@
+package task_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command/task"
+	"go-mod.ewintr.nl/planner/plan/storage/memory"
+)
+
+func TestEditParse(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		main   []string
+		expLID int
+		expErr bool
+	}{
+		{
+			name:   "empty",
+			main:   []string{},
+			expErr: true,
+		},
+		{
+			name:   "lid first",
+			main:   []string{"3", "edit"},
+			expLID: 3,
+		},
+		{
+			name:   "edit first",
+			main:   []string{"edit", "4"},
+			expLID: 4,
+		},
+		{
+			name:   "not a lid",
+			main:   []string{"edit", "four"},
+			expErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, actErr := task.NewEditArgs().Parse(tc.main, nil)
+			if tc.expErr != (actErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expErr, actErr)
+			}
+			if tc.expErr {
+				return
+			}
+			editCmd, ok := cmd.(*task.Edit)
+			if !ok {
+				t.Errorf("exp true, got false")
+			}
+			if editCmd.Args.LocalID != tc.expLID {
+				t.Errorf("exp %v, got %v", tc.expLID, editCmd.Args.LocalID)
+			}
+			if editCmd.Args.Editor == nil {
+				t.Errorf("exp editor, got nil")
+			}
+		})
+	}
+}
+
+func TestEdit(t *testing.T) {
+	t.Parallel()
+
+	tsk := item.Task{
+		ID:   "id",
+		Date: item.NewDate(2024, 10, 7),
+		TaskBody: item.TaskBody{
+			Title:    "title",
+			Project:  "project",
+			Duration: time.Hour,
+			Tags:     []string{"home"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name       string
+		edit       func(text string) string
+		expErr     bool
+		expTask    item.Task
+		expUpdated int
+	}{
+		{
+			name:    "unchanged",
+			edit:    func(text string) string { return text },
+			expTask: tsk,
+		},
+		{
+			name: "title and notes",
+			edit: func(text string) string {
+				text = strings.Replace(text, "title\n", "new title\n", 1)
+				return text + "first line\nsecond line\n"
+			},
+			expTask: item.Task{
+				ID:   "id",
+				Date: item.NewDate(2024, 10, 7),
+				TaskBody: item.TaskBody{
+					Title:    "new title",
+					Project:  "project",
+					Duration: time.Hour,
+					Tags:     []string{"home"},
+					Notes:    "first line\nsecond line",
+				},
+			},
+			expUpdated: 1,
+		},
+		{
+			name: "fields",
+			edit: func(text string) string {
+				text = strings.Replace(text, "date: 2024-10-07", "date: 2024-10-09", 1)
+				text = strings.Replace(text, "tags: @home", "tags: @home @phone", 1)
+				text = strings.Replace(text, "priority: ", "priority: p2", 1)
+				return strings.Replace(text, "project: project\n", "", 1)
+			},
+			expTask: item.Task{
+				ID:   "id",
+				Date: item.NewDate(2024, 10, 9),
+				TaskBody: item.TaskBody{
+					Title:    "title",
+					Duration: time.Hour,
+					Priority: item.Priority2,
+					Tags:     []string{"home", "phone"},
+				},
+			},
+			expUpdated: 1,
+		},
+		{
+			name: "invalid date",
+			edit: func(text string) string {
+				return strings.Replace(text, "date: 2024-10-07", "date: someday", 1)
+			},
+			expErr: true,
+		},
+		{
+			name: "unknown field",
+			edit: func(text string) string {
+				return strings.Replace(text, "time: ", "color: red", 1)
+			},
+			expErr: true,
+		},
+		{
+			name: "empty title",
+			edit: func(text string) string {
+				return strings.Replace(text, "title\n", "\n", 1)
+			},
+			expErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mems := memory.New()
+			if err := mems.Task(nil).Store(tsk); err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if err := mems.LocalID(nil).Store(tsk.ID, 1); err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+
+			cmd := task.Edit{
+				Args: task.EditArgs{
+					LocalID: 1,
+					Editor: func(text string) (string, error) {
+						return tc.edit(text), nil
+					},
+				},
+			}
+			_, actErr := cmd.Do(mems, nil)
+			if tc.expErr != (actErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expErr, actErr)
+			}
+			if tc.expErr {
+				return
+			}
+
+			actTask, err := mems.Task(nil).FindOne(tsk.ID)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if diff := item.TaskDiff(tc.expTask, actTask); diff != "" {
+				t.Errorf("(exp -, got +)\n%s", diff)
+			}
+			updated, err := mems.Sync(nil).FindAll()
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if len(updated) != tc.expUpdated {
+				t.Errorf("exp %v, got %v", tc.expUpdated, len(updated))
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package task
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"go-mod.ewintr.nl/planner/item"
@@ -20,20 +23,60 @@ type ListArgs struct {
 	From        item.Date
 	To          item.Date
 	Project     string
+	InstancesOf int
+	Priority    item.Priority
+	Tags        []string
+	NotTags     []string
+	ByUrgency   bool
+	// blocked tasks are hidden, unless asked for
+	ShowBlocked bool
+	OnlyBlocked bool
+	// so are snoozed tasks
+	ShowHidden bool
+	OnlyHidden bool
+	// DeadlineWindow is the number of days before a deadline in which it is
+	// pointed out
+	DeadlineWindow int
 }
 
-func NewListArgs() ListArgs {
+// NewListArgs takes the number of days before a deadline in which it is
+// pointed out. Zero or less means the default.
+func NewListArgs(deadlineWindow int) ListArgs {
+	if deadlineWindow <= 0 {
+		deadlineWindow = DefaultDeadlineWindow
+	}
+
 	return ListArgs{
+		DeadlineWindow: deadlineWindow,
 		fieldTPL: map[string][]string{
 			"project":   {"p", "project"},
 			"from":      {"f", "from"},
 			"to":        {"t", "to"},
 			"recurring": {"rec", "recurring"},
+			"instances": {"inst", "instances"},
+			"priority":  {"prio", "priority"},
+			"sort":      {"sort"},
+			"tags":      {"tag", "tags"},
+			"blocked":   {"blocked"},
+			"hidden":    {"hidden"},
 		},
 	}
 }
 
+// Parse also accepts @tag and -@tag to only show tasks with, or without, a
+// tag. The tags field does the same with a comma separated list, like
+// tags:home,-phone.
 func (la ListArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
+	tagFilters := make([]string, 0)
+	rest := make([]string, 0, len(main))
+	for _, m := range main {
+		if strings.HasPrefix(m, "@") || strings.HasPrefix(m, "-@") {
+			tagFilters = append(tagFilters, m)
+			continue
+		}
+		rest = append(rest, m)
+	}
+	main = rest
 	if len(main) > 1 {
 		return nil, command.ErrWrongCommand
 	}
@@ -42,9 +85,19 @@ func (la ListArgs) Parse(main []string, fields map[string]string) (command.Comma
 	if err != nil {
 		return nil, err
 	}
+	if val, ok := fields["tags"]; ok {
+		tagFilters = append(tagFilters, splitTags(val)...)
+	}
+	var tags, notTags []string
+	for _, tf := range tagFilters {
+		if tag, ok := strings.CutPrefix(strings.TrimSpace(tf), "-"); ok {
+			notTags = append(notTags, tag)
+			continue
+		}
+		tags = append(tags, tf)
+	}
 
-	now := time.Now()
-	today := item.NewDate(now.Year(), int(now.Month()), now.Day())
+	today := item.Today()
 
 	switch len(main) {
 	case 0:
@@ -74,9 +127,15 @@ func (la ListArgs) Parse(main []string, fields map[string]string) (command.Comma
 	var project string
 	if val, ok := fields["from"]; ok {
 		fromDate = item.NewDateFromString(val)
+		if fromDate.IsZero() {
+			return nil, fmt.Errorf("%w: could not parse from date", command.ErrInvalidArg)
+		}
 	}
 	if val, ok := fields["to"]; ok {
 		toDate = item.NewDateFromString(val)
+		if toDate.IsZero() {
+			return nil, fmt.Errorf("%w: could not parse to date", command.ErrInvalidArg)
+		}
 	}
 	if val, ok := fields["recurrer"]; ok && val == "true" {
 		hasRecurrer = true
@@ -84,6 +143,54 @@ func (la ListArgs) Parse(main []string, fields map[string]string) (command.Comma
 	if val, ok := fields["project"]; ok {
 		project = val
 	}
+	var instancesOf int
+	if val, ok := fields["instances"]; ok {
+		lid, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("%w: not a local id: %v", command.ErrInvalidArg, val)
+		}
+		instancesOf = lid
+	}
+	var priority item.Priority
+	if val, ok := fields["priority"]; ok {
+		if priority, err = item.ParsePriority(val); err != nil {
+			return nil, fmt.Errorf("%w: %v", command.ErrInvalidArg, err)
+		}
+	}
+	var byUrgency bool
+	if val, ok := fields["sort"]; ok {
+		switch val {
+		case "date":
+		case "urgency":
+			byUrgency = true
+		default:
+			return nil, fmt.Errorf("%w: sort must be date or urgency", command.ErrInvalidArg)
+		}
+	}
+	var showBlocked, onlyBlocked bool
+	if val, ok := fields["blocked"]; ok {
+		switch val {
+		case "hide":
+		case "show":
+			showBlocked = true
+		case "only":
+			onlyBlocked = true
+		default:
+			return nil, fmt.Errorf("%w: blocked must be hide, show or only", command.ErrInvalidArg)
+		}
+	}
+	var showHidden, onlyHidden bool
+	if val, ok := fields["hidden"]; ok {
+		switch val {
+		case "hide":
+		case "show":
+			showHidden = true
+		case "only":
+			onlyHidden = true
+		default:
+			return nil, fmt.Errorf("%w: hidden must be hide, show or only", command.ErrInvalidArg)
+		}
+	}
 
 	return List{
 		Args: ListArgs{
@@ -91,6 +198,17 @@ func (la ListArgs) Parse(main []string, fields map[string]string) (command.Comma
 			From:        fromDate,
 			To:          toDate,
 			Project:     project,
+			InstancesOf: instancesOf,
+			Priority:    priority,
+			Tags:        item.NewTags(tags...),
+			NotTags:     item.NewTags(notTags...),
+			ByUrgency:   byUrgency,
+			ShowBlocked: showBlocked,
+			OnlyBlocked: onlyBlocked,
+			ShowHidden:  showHidden,
+			OnlyHidden:  onlyHidden,
+			// keep the configured window
+			DeadlineWindow: la.DeadlineWindow,
 		},
 	}, nil
 }
@@ -110,40 +228,106 @@ func (list List) Do(repos command.Repositories, _ client.Client) (command.Comman
 	if err != nil {
 		return nil, fmt.Errorf("could not get local ids: %v", err)
 	}
+	var recurParent string
+	if list.Args.InstancesOf != 0 {
+		recurParent, err = repos.LocalID(tx).FindOne(list.Args.InstancesOf)
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			return nil, fmt.Errorf("could not find local id")
+		case err != nil:
+			return nil, err
+		}
+	}
 	all, err := repos.Task(tx).FindMany(storage.TaskListParams{
 		HasRecurrer: list.Args.HasRecurrer,
 		From:        list.Args.From,
 		To:          list.Args.To,
 		Project:     list.Args.Project,
+		RecurParent: recurParent,
+		Priority:    list.Args.Priority,
+		Tags:        list.Args.Tags,
+		NotTags:     list.Args.NotTags,
 	})
 	if err != nil {
 		return nil, err
 	}
+	// subtasks are shown with their parent, even when they do not match, and
+	// blockers can be anywhere. Get them all at once, instead of asking for
+	// them per task
+	everything, err := repos.Task(tx).FindMany(storage.TaskListParams{})
+	if err != nil {
+		return nil, err
+	}
+	subtasks := subtasksByParent(everything)
+	open := byID(everything)
+	listed := make(map[string]bool, len(all))
+	for _, tsk := range all {
+		listed[tsk.ID] = true
+	}
+	for _, tsk := range all {
+		if tsk.Parent != "" {
+			continue
+		}
+		for _, st := range subtasks[tsk.ID] {
+			if !listed[st.ID] {
+				all = append(all, st)
+			}
+		}
+	}
 
+	today := item.Today()
 	res := make([]TaskWithLID, 0, len(all))
 	for _, tsk := range all {
 		lid, ok := localIDs[tsk.ID]
 		if !ok {
 			return nil, fmt.Errorf("could not find local id for %s", tsk.ID)
 		}
+		hidden := tsk.Hidden(today)
+		if (hidden && !list.Args.ShowHidden && !list.Args.OnlyHidden) || (!hidden && list.Args.OnlyHidden) {
+			continue
+		}
+		blocked := isBlocked(tsk, open)
+		if (blocked && !list.Args.ShowBlocked && !list.Args.OnlyBlocked) || (!blocked && list.Args.OnlyBlocked) {
+			continue
+		}
 		res = append(res, TaskWithLID{
-			LocalID: lid,
-			Task:    tsk,
+			LocalID:       lid,
+			ParentLocalID: localIDs[tsk.Parent],
+			Blocked:       blocked,
+			Task:          tsk,
 		})
 	}
 
 	return ListResult{
-		Tasks: res,
+		Tasks:          res,
+		ByUrgency:      list.Args.ByUrgency,
+		DeadlineWindow: list.Args.DeadlineWindow,
 	}, nil
 }
 
 type TaskWithLID struct {
 	LocalID int
+	// ParentLocalID is set for a subtask, if the parent is known
+	ParentLocalID int
+	// Blocked tells that the task waits for another
+	Blocked bool
 	Task    item.Task
 }
 
+// localRef shows a subtask by its parent and position, and other tasks by
+// their local id
+func (tl TaskWithLID) localRef() string {
+	if tl.ParentLocalID != 0 {
+		return formatLocalRef(tl.ParentLocalID, tl.Task.Position)
+	}
+
+	return formatLocalRef(tl.LocalID, 0)
+}
+
 type ListResult struct {
-	Tasks []TaskWithLID
+	Tasks          []TaskWithLID
+	ByUrgency      bool
+	DeadlineWindow int
 }
 
 func (lr ListResult) Render() string {
@@ -151,30 +335,29 @@ func (lr ListResult) Render() string {
 		return "\nno tasks to display\n"
 	}
 
-	sort.Slice(lr.Tasks, func(i, j int) bool {
-		if lr.Tasks[i].Task.Date.After(lr.Tasks[j].Task.Date) {
-			return false
-		}
-		if lr.Tasks[j].Task.Date.After(lr.Tasks[i].Task.Date) {
-			return true
+	today := item.Today()
+	if lr.ByUrgency {
+		lr.sortByUrgency(today)
+	} else {
+		lr.sortByDate()
+	}
+	var nested map[string]bool
+	lr.Tasks, nested = lr.nestSubtasks()
+
+	var showRec, showTime, showDur, showPrio, showTags, showBlocked, showDeadline, showPost, showSnoozed bool
+	for _, tl := range lr.Tasks {
+		if tl.Task.Hidden(today) {
+			showSnoozed = true
 		}
-		if lr.Tasks[i].Task.Project < lr.Tasks[j].Task.Project {
-			return true
+		if tl.Task.Postponed > 0 {
+			showPost = true
 		}
-		if lr.Tasks[i].Task.Project > lr.Tasks[j].Task.Project {
-			return false
+		if !tl.Task.Deadline.IsZero() {
+			showDeadline = true
 		}
-		if lr.Tasks[i].Task.Recurrer == nil && lr.Tasks[j].Task.Recurrer != nil {
-			return true
-		}
-		if lr.Tasks[i].Task.Recurrer != nil && lr.Tasks[j].Task.Recurrer == nil {
-			return false
+		if tl.Blocked {
+			showBlocked = true
 		}
-		return lr.Tasks[i].LocalID < lr.Tasks[j].LocalID
-	})
-
-	var showRec, showTime, showDur bool
-	for _, tl := range lr.Tasks {
 		if tl.Task.Recurrer != nil {
 			showRec = true
 		}
@@ -184,24 +367,55 @@ func (lr ListResult) Render() string {
 		if !tl.Task.Time.IsZero() {
 			showTime = true
 		}
+		if tl.Task.Priority != item.PriorityNone {
+			showPrio = true
+		}
+		if len(tl.Task.Tags) > 0 {
+			showTags = true
+		}
 	}
 
 	title := []string{"id"}
+	if showBlocked {
+		title = append(title, "blk")
+	}
 	if showRec {
 		title = append(title, "rec")
 	}
+	if showPrio {
+		title = append(title, "prio")
+	}
 	title = append(title, "project", "date")
+	if showDeadline {
+		title = append(title, "due")
+	}
 	if showTime {
 		title = append(title, "time")
 	}
 	if showDur {
 		title = append(title, "dur")
 	}
+	if showPost {
+		title = append(title, "post")
+	}
+	if showSnoozed {
+		title = append(title, "snoozed")
+	}
+	if showTags {
+		title = append(title, "tags")
+	}
 	title = append(title, "title")
 
 	data := [][]string{title}
 	for _, tl := range lr.Tasks {
-		row := []string{fmt.Sprintf("%d", tl.LocalID)}
+		row := []string{tl.localRef()}
+		if showBlocked {
+			blkStr := ""
+			if tl.Blocked {
+				blkStr = "*"
+			}
+			row = append(row, blkStr)
+		}
 		if showRec {
 			recStr := ""
 			if tl.Task.Recurrer != nil {
@@ -209,7 +423,13 @@ func (lr ListResult) Render() string {
 			}
 			row = append(row, recStr)
 		}
+		if showPrio {
+			row = append(row, tl.Task.Priority.String())
+		}
 		row = append(row, tl.Task.Project, tl.Task.Date.String())
+		if showDeadline {
+			row = append(row, formatDeadline(tl.Task, today, lr.DeadlineWindow))
+		}
 		if showTime {
 			row = append(row, tl.Task.Time.String())
 		}
@@ -220,9 +440,98 @@ func (lr ListResult) Render() string {
 			}
 			row = append(row, durStr)
 		}
-		row = append(row, tl.Task.Title)
+		if showPost {
+			postStr := ""
+			if tl.Task.Postponed > 0 {
+				postStr = fmt.Sprintf("%d", tl.Task.Postponed)
+			}
+			row = append(row, postStr)
+		}
+		if showSnoozed {
+			snoozeStr := ""
+			if tl.Task.Hidden(today) {
+				snoozeStr = tl.Task.SnoozeUntil.String()
+			}
+			row = append(row, snoozeStr)
+		}
+		if showTags {
+			row = append(row, formatTags(tl.Task.Tags))
+		}
+		title := tl.Task.Title
+		if nested[tl.Task.ID] {
+			title = "  " + title
+		}
+		row = append(row, title)
 		data = append(data, row)
 	}
 
 	return fmt.Sprintf("\n%s\n", format.Table(data))
 }
+
+// nestSubtasks moves subtasks right below their parent, in order of
+// position, when the parent is in the list too. It returns the ids of the
+// subtasks that were moved.
+func (lr ListResult) nestSubtasks() ([]TaskWithLID, map[string]bool) {
+	present := make(map[string]bool, len(lr.Tasks))
+	for _, tl := range lr.Tasks {
+		present[tl.Task.ID] = true
+	}
+	nested := make(map[string]bool)
+	children := make(map[string][]TaskWithLID)
+	for _, tl := range lr.Tasks {
+		if tl.Task.Parent != "" && present[tl.Task.Parent] {
+			nested[tl.Task.ID] = true
+			children[tl.Task.Parent] = append(children[tl.Task.Parent], tl)
+		}
+	}
+
+	res := make([]TaskWithLID, 0, len(lr.Tasks))
+	for _, tl := range lr.Tasks {
+		if nested[tl.Task.ID] {
+			continue
+		}
+		res = append(res, tl)
+		subtasks := children[tl.Task.ID]
+		sort.Slice(subtasks, func(i, j int) bool {
+			return subtasks[i].Task.Position < subtasks[j].Task.Position
+		})
+		res = append(res, subtasks...)
+	}
+
+	return res, nested
+}
+
+// sortByUrgency puts the most urgent tasks first
+func (lr ListResult) sortByUrgency(today item.Date) {
+	sort.Slice(lr.Tasks, func(i, j int) bool {
+		ui, uj := lr.Tasks[i].Task.Urgency(today), lr.Tasks[j].Task.Urgency(today)
+		if ui != uj {
+			return ui > uj
+		}
+		return lr.Tasks[i].LocalID < lr.Tasks[j].LocalID
+	})
+}
+
+func (lr ListResult) sortByDate() {
+	sort.Slice(lr.Tasks, func(i, j int) bool {
+		if lr.Tasks[i].Task.Date.After(lr.Tasks[j].Task.Date) {
+			return false
+		}
+		if lr.Tasks[j].Task.Date.After(lr.Tasks[i].Task.Date) {
+			return true
+		}
+		if lr.Tasks[i].Task.Project < lr.Tasks[j].Task.Project {
+			return true
+		}
+		if lr.Tasks[i].Task.Project > lr.Tasks[j].Task.Project {
+			return false
+		}
+		if lr.Tasks[i].Task.Recurrer == nil && lr.Tasks[j].Task.Recurrer != nil {
+			return true
+		}
+		if lr.Tasks[i].Task.Recurrer != nil && lr.Tasks[j].Task.Recurrer == nil {
+			return false
+		}
+		return lr.Tasks[i].LocalID < lr.Tasks[j].LocalID
+	})
+}
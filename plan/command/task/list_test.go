@@ -2,7 +2,6 @@ package task_test
 
 import (
 	"testing"
-	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -13,8 +12,7 @@ import (
 
 func TestListParse(t *testing.T) {
 	t.Parallel()
-	now := time.Now()
-	today := item.NewDate(now.Year(), int(now.Month()), now.Day())
+	today := item.Today()
 
 	for _, tc := range []struct {
 		name    string
@@ -55,9 +53,86 @@ func TestListParse(t *testing.T) {
 				To:   today.Add(7),
 			},
 		},
+		{
+			name: "natural dates",
+			main: []string{},
+			fields: map[string]string{
+				"from": "tomorrow",
+				"to":   "in 3 days",
+			},
+			expArgs: task.ListArgs{
+				From: today.Add(1),
+				To:   today.Add(3),
+			},
+		},
+		{
+			name: "invalid date",
+			main: []string{},
+			fields: map[string]string{
+				"from": "someday",
+			},
+			expErr: true,
+		},
+		{
+			name: "priority and urgency",
+			main: []string{},
+			fields: map[string]string{
+				"prio": "p1",
+				"sort": "urgency",
+			},
+			expArgs: task.ListArgs{
+				Priority:  item.Priority1,
+				ByUrgency: true,
+			},
+		},
+		{
+			name: "tags",
+			main: []string{"@home", "-@Phone"},
+			fields: map[string]string{
+				"tag": "work,-call",
+			},
+			expArgs: task.ListArgs{
+				Tags:    []string{"home", "work"},
+				NotTags: []string{"call", "phone"},
+			},
+		},
+		{
+			name: "invalid priority",
+			main: []string{},
+			fields: map[string]string{
+				"prio": "high",
+			},
+			expErr: true,
+		},
+		{
+			name: "invalid sort",
+			main: []string{},
+			fields: map[string]string{
+				"sort": "title",
+			},
+			expErr: true,
+		},
+		{
+			name: "instances",
+			main: []string{},
+			fields: map[string]string{
+				"inst": "3",
+			},
+			expArgs: task.ListArgs{
+				InstancesOf: 3,
+			},
+		},
+		{
+			name: "instances invalid",
+			main: []string{},
+			fields: map[string]string{
+				"inst": "three",
+			},
+			expErr: true,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			nla := task.NewListArgs()
+			nla := task.NewListArgs(0)
 			cmd, actErr := nla.Parse(tc.main, tc.fields)
 			if tc.expErr != (actErr != nil) {
 				t.Errorf("exp %v, got %v", tc.expErr, actErr != nil)
@@ -69,6 +144,7 @@ func TestListParse(t *testing.T) {
 			if !ok {
 				t.Errorf("exp true, got false")
 			}
+			tc.expArgs.DeadlineWindow = task.DefaultDeadlineWindow
 			if diff := cmp.Diff(tc.expArgs, listCmd.Args, cmpopts.IgnoreTypes(map[string][]string{})); diff != "" {
 				t.Errorf("(+exp, -got)\n%s\n", diff)
 			}
@@ -94,6 +170,23 @@ func TestList(t *testing.T) {
 	if err := mems.LocalID(nil).Store(e.ID, 1); err != nil {
 		t.Errorf("exp nil, got %v", err)
 	}
+	inst := item.Task{
+		ID:          "inst",
+		Date:        item.NewDate(2024, 10, 8),
+		RecurParent: "parent",
+		TaskBody: item.TaskBody{
+			Title: "instance",
+		},
+	}
+	if err := mems.Task(nil).Store(inst); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if err := mems.LocalID(nil).Store(inst.ID, 2); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if err := mems.LocalID(nil).Store("parent", 3); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
 
 	for _, tc := range []struct {
 		name   string
@@ -113,6 +206,23 @@ func TestList(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "instances",
+			cmd: task.List{
+				Args: task.ListArgs{
+					InstancesOf: 3,
+				},
+			},
+			expRes: true,
+		},
+		{
+			name: "no instances",
+			cmd: task.List{
+				Args: task.ListArgs{
+					InstancesOf: 1,
+				},
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			res, err := tc.cmd.Do(mems, nil)
@@ -128,3 +238,35 @@ func TestList(t *testing.T) {
 		})
 	}
 }
+
+func TestListSubtasks(t *testing.T) {
+	t.Parallel()
+
+	mems := memory.New()
+	for i, tsk := range []item.Task{
+		{ID: "p", TaskBody: item.TaskBody{Title: "parent", Project: "home"}},
+		{ID: "s2", TaskBody: item.TaskBody{Title: "second", Parent: "p", Position: 2}},
+		{ID: "s1", TaskBody: item.TaskBody{Title: "first", Parent: "p", Position: 1}},
+		{ID: "o", TaskBody: item.TaskBody{Title: "other", Project: "work"}},
+		{ID: "os", TaskBody: item.TaskBody{Title: "other sub", Parent: "o", Position: 1}},
+	} {
+		if err := mems.Task(nil).Store(tsk); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+	}
+
+	res, err := task.List{Args: task.ListArgs{Project: "home"}}.Do(mems, nil)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	act := make([]string, 0)
+	for _, tl := range res.(task.ListResult).Tasks {
+		act = append(act, tl.Task.Title)
+	}
+	if diff := cmp.Diff([]string{"parent", "first", "second"}, act); diff != "" {
+		t.Errorf("(exp -, got +)\n%s", diff)
+	}
+}
@@ -0,0 +1,143 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/cli/arg"
+	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/format"
+	"go-mod.ewintr.nl/planner/plan/storage"
+	"go-mod.ewintr.nl/planner/sync/client"
+)
+
+type NextArgs struct {
+	fieldTPL map[string][]string
+	Project  string
+}
+
+func NewNextArgs() NextArgs {
+	return NextArgs{
+		fieldTPL: map[string][]string{
+			"project": {"p", "project"},
+		},
+	}
+}
+
+func (na NextArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
+	if len(main) != 1 || main[0] != "next" {
+		return nil, command.ErrWrongCommand
+	}
+	fields, err := arg.ResolveFields(fields, na.fieldTPL)
+	if err != nil {
+		return nil, err
+	}
+
+	return Next{
+		Args: NextArgs{
+			Project: fields["project"],
+		},
+	}, nil
+}
+
+// Next shows the tasks that can be worked on now, per project. Those are
+// the tasks that are not planned for later or snoozed, do not wait for
+// another task and have no open subtasks.
+type Next struct {
+	Args NextArgs
+}
+
+func (n Next) Do(repos command.Repositories, _ client.Client) (command.CommandResult, error) {
+	tx, err := repos.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	localIDs, err := repos.LocalID(tx).FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not get local ids: %v", err)
+	}
+	all, err := repos.Task(tx).FindMany(storage.TaskListParams{
+		Project: n.Args.Project,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// blockers and subtasks can be in other projects
+	everything, err := repos.Task(tx).FindMany(storage.TaskListParams{})
+	if err != nil {
+		return nil, err
+	}
+	open := byID(everything)
+	hasSubtasks := make(map[string]bool)
+	for _, tsk := range everything {
+		if tsk.Parent != "" {
+			hasSubtasks[tsk.Parent] = true
+		}
+	}
+
+	today := item.Today()
+	projects := make(map[string][]TaskWithLID)
+	for _, tsk := range all {
+		if tsk.Recurrer != nil || tsk.Date.After(today) || tsk.Hidden(today) || hasSubtasks[tsk.ID] {
+			continue
+		}
+		if isBlocked(tsk, open) {
+			continue
+		}
+		lid, ok := localIDs[tsk.ID]
+		if !ok {
+			return nil, fmt.Errorf("could not find local id for %s", tsk.ID)
+		}
+		projects[tsk.Project] = append(projects[tsk.Project], TaskWithLID{
+			LocalID:       lid,
+			ParentLocalID: localIDs[tsk.Parent],
+			Task:          tsk,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not list next tasks: %v", err)
+	}
+
+	return NextResult{
+		Projects: projects,
+	}, nil
+}
+
+type NextResult struct {
+	Projects map[string][]TaskWithLID
+}
+
+func (nr NextResult) Render() string {
+	if len(nr.Projects) == 0 {
+		return "\nno tasks to display\n"
+	}
+
+	projects := make([]string, 0, len(nr.Projects))
+	for pr := range nr.Projects {
+		projects = append(projects, pr)
+	}
+	sort.Strings(projects)
+
+	today := item.Today()
+	data := [][]string{{"project", "id", "prio", "date", "title"}}
+	for _, pr := range projects {
+		tasks := ListResult{Tasks: nr.Projects[pr]}
+		tasks.sortByUrgency(today)
+		for i, tl := range tasks.Tasks {
+			prStr := ""
+			if i == 0 {
+				prStr = pr
+				if pr == "" {
+					prStr = "-"
+				}
+			}
+			data = append(data, []string{prStr, tl.localRef(), tl.Task.Priority.String(), tl.Task.Date.String(), tl.Task.Title})
+		}
+	}
+
+	return fmt.Sprintf("\n%s\n", format.Table(data))
+}
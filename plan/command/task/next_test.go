@@ -0,0 +1,213 @@
+package task_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command/task"
+	"go-mod.ewintr.nl/planner/plan/storage/memory"
+)
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	today := item.Today()
+	mems := memory.New()
+	for i, tsk := range []item.Task{
+		{ID: "a", TaskBody: item.TaskBody{Title: "a", Project: "house"}},
+		{ID: "b", TaskBody: item.TaskBody{Title: "b", Project: "house", BlockedBy: []string{"a"}}},
+		{ID: "c", TaskBody: item.TaskBody{Title: "c", BlockedBy: []string{"done"}}},
+		{ID: "d", Date: today.Add(1), TaskBody: item.TaskBody{Title: "d"}},
+		{ID: "e", Date: today, TaskBody: item.TaskBody{Title: "e", Project: "work"}},
+		{ID: "f", TaskBody: item.TaskBody{Title: "f", Project: "work"}},
+		{ID: "g", TaskBody: item.TaskBody{Title: "g", Project: "work", Parent: "f", Position: 1}},
+		{ID: "h", TaskBody: item.TaskBody{Title: "h", Project: "house"}},
+		{ID: "i", TaskBody: item.TaskBody{Title: "i", Project: "work", Parent: "h", Position: 1}},
+	} {
+		if err := mems.Task(nil).Store(tsk); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		fields map[string]string
+		exp    map[string][]string
+	}{
+		{
+			name: "all",
+			exp: map[string][]string{
+				"":      {"c"},
+				"house": {"a"},
+				"work":  {"e", "g", "i"},
+			},
+		},
+		{
+			name:   "project",
+			fields: map[string]string{"p": "house"},
+			exp: map[string][]string{
+				"house": {"a"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := task.NewNextArgs().Parse([]string{"next"}, tc.fields)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			res, err := cmd.Do(mems, nil)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			act := make(map[string][]string)
+			for pr, tasks := range res.(task.NextResult).Projects {
+				for _, tl := range tasks {
+					act[pr] = append(act[pr], tl.Task.Title)
+				}
+			}
+			if diff := cmp.Diff(tc.exp, act); diff != "" {
+				t.Errorf("(exp -, got +)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestListBlocked(t *testing.T) {
+	t.Parallel()
+
+	mems := memory.New()
+	for i, tsk := range []item.Task{
+		{ID: "a", TaskBody: item.TaskBody{Title: "a"}},
+		{ID: "b", TaskBody: item.TaskBody{Title: "b", BlockedBy: []string{"a"}}},
+	} {
+		if err := mems.Task(nil).Store(tsk); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		args task.ListArgs
+		exp  []string
+	}{
+		{
+			name: "hidden",
+			exp:  []string{"a"},
+		},
+		{
+			name: "show",
+			args: task.ListArgs{ShowBlocked: true},
+			exp:  []string{"a", "b"},
+		},
+		{
+			name: "only",
+			args: task.ListArgs{OnlyBlocked: true},
+			exp:  []string{"b"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := task.List{Args: tc.args}.Do(mems, nil)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			act := make([]string, 0)
+			for _, tl := range res.(task.ListResult).Tasks {
+				act = append(act, tl.Task.Title)
+			}
+			if diff := cmp.Diff(tc.exp, act); diff != "" {
+				t.Errorf("(exp -, got +)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBlockedByCycle(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		main   []string
+		fields map[string]string
+		expErr bool
+		exp    []string
+	}{
+		{
+			name:   "block",
+			main:   []string{"1", "update"},
+			fields: map[string]string{"after": "3"},
+			exp:    []string{"c"},
+		},
+		{
+			name:   "clear",
+			main:   []string{"2", "update"},
+			fields: map[string]string{"after": ""},
+		},
+		{
+			name:   "self",
+			main:   []string{"1", "update"},
+			fields: map[string]string{"after": "1"},
+			expErr: true,
+		},
+		{
+			name:   "cycle",
+			main:   []string{"1", "update"},
+			fields: map[string]string{"after": "2"},
+			expErr: true,
+		},
+		{
+			name:   "unknown",
+			main:   []string{"1", "update"},
+			fields: map[string]string{"after": "9"},
+			expErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mems := memory.New()
+			// b waits for a
+			for i, tsk := range []item.Task{
+				{ID: "a", TaskBody: item.TaskBody{Title: "a"}},
+				{ID: "b", TaskBody: item.TaskBody{Title: "b", BlockedBy: []string{"a"}}},
+				{ID: "c", TaskBody: item.TaskBody{Title: "c"}},
+			} {
+				if err := mems.Task(nil).Store(tsk); err != nil {
+					t.Errorf("exp nil, got %v", err)
+				}
+				if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+					t.Errorf("exp nil, got %v", err)
+				}
+			}
+
+			cmd, err := task.NewUpdateArgs().Parse(tc.main, tc.fields)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			_, actErr := cmd.Do(mems, nil)
+			if tc.expErr != (actErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expErr, actErr)
+			}
+			if tc.expErr {
+				return
+			}
+			lid, _ := strconv.Atoi(tc.main[0])
+			id, err := mems.LocalID(nil).FindOne(lid)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			actTask, err := mems.Task(nil).FindOne(id)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if diff := cmp.Diff(tc.exp, actTask.BlockedBy); diff != "" {
+				t.Errorf("(exp -, got +)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/cli/arg"
+	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/format"
+	"go-mod.ewintr.nl/planner/plan/storage"
+	"go-mod.ewintr.nl/planner/sync/client"
+)
+
+const defaultOccurrences = 10
+
+var occurrencesFieldTPL = map[string][]string{
+	"n": {"n"},
+}
+
+func parseOccurrencesN(fields map[string]string) (int, error) {
+	fields, err := arg.ResolveFields(fields, occurrencesFieldTPL)
+	if err != nil {
+		return 0, err
+	}
+	val, ok := fields["n"]
+	if !ok {
+		return defaultOccurrences, nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%w: n must be a positive number", command.ErrInvalidArg)
+	}
+
+	return n, nil
+}
+
+type RecurPreviewArgs struct {
+	Recurrer item.Recurrer
+	N        int
+}
+
+func NewRecurPreviewArgs() RecurPreviewArgs {
+	return RecurPreviewArgs{}
+}
+
+// Parse accepts "recur preview <recurrer>". The recurrer may be split over
+// several arguments when it is not quoted.
+func (rpa RecurPreviewArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
+	if len(main) < 2 || main[0] != "recur" || main[1] != "preview" {
+		return nil, command.ErrWrongCommand
+	}
+	if len(main) == 2 {
+		return nil, fmt.Errorf("%w: missing recurrer", command.ErrInvalidArg)
+	}
+	rec := item.ParseRecurrer(strings.Join(main[2:], " "), item.Today())
+	if rec == nil {
+		return nil, fmt.Errorf("%w: could not parse recurrer", command.ErrInvalidArg)
+	}
+	n, err := parseOccurrencesN(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecurPreview{
+		Args: RecurPreviewArgs{
+			Recurrer: rec,
+			N:        n,
+		},
+	}, nil
+}
+
+type RecurPreview struct {
+	Args RecurPreviewArgs
+}
+
+func (rp RecurPreview) Do(_ command.Repositories, _ client.Client) (command.CommandResult, error) {
+	return OccurrencesResult{
+		Recurrer: rp.Args.Recurrer,
+		Dates:    item.Occurrences(rp.Args.Recurrer, rp.Args.Recurrer.First(), rp.Args.N),
+		N:        rp.Args.N,
+	}, nil
+}
+
+type OccurrencesArgs struct {
+	LocalID int
+	N       int
+}
+
+func NewOccurrencesArgs() OccurrencesArgs {
+	return OccurrencesArgs{}
+}
+
+// Parse accepts "<lid> occurrences" and "occurrences <lid>"
+func (oa OccurrencesArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
+	if len(main) != 2 {
+		return nil, command.ErrWrongCommand
+	}
+	aliases := []string{"occ", "occurrences"}
+	var localIDStr string
+	switch {
+	case slices.Contains(aliases, main[0]):
+		localIDStr = main[1]
+	case slices.Contains(aliases, main[1]):
+		localIDStr = main[0]
+	default:
+		return nil, command.ErrWrongCommand
+	}
+	localID, err := strconv.Atoi(localIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("not a local id: %v", localIDStr)
+	}
+	n, err := parseOccurrencesN(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Occurrences{
+		Args: OccurrencesArgs{
+			LocalID: localID,
+			N:       n,
+		},
+	}, nil
+}
+
+type Occurrences struct {
+	Args OccurrencesArgs
+}
+
+func (o Occurrences) Do(repos command.Repositories, _ client.Client) (command.CommandResult, error) {
+	tx, err := repos.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	id, err := repos.LocalID(tx).FindOne(o.Args.LocalID)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return nil, fmt.Errorf("could not find local id")
+	case err != nil:
+		return nil, err
+	}
+	tsk, err := repos.Task(tx).FindOne(id)
+	if err != nil {
+		return nil, fmt.Errorf("could not find task")
+	}
+	parent, err := findSeries(repos.Task(tx), tsk)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not list occurrences: %v", err)
+	}
+
+	return OccurrencesResult{
+		Title:    parent.Title,
+		Recurrer: parent.Recurrer,
+		Dates:    item.Occurrences(parent.Recurrer, item.Today(), o.Args.N),
+		N:        o.Args.N,
+	}, nil
+}
+
+type OccurrencesResult struct {
+	Title    string
+	Recurrer item.Recurrer
+	Dates    []item.Date
+	N        int
+}
+
+func (ocr OccurrencesResult) Render() string {
+	header := format.Bold(ocr.Recurrer.String())
+	if ocr.Title != "" {
+		header = fmt.Sprintf("%s: %s", format.Bold(ocr.Title), ocr.Recurrer.String())
+	}
+	if len(ocr.Dates) == 0 {
+		return fmt.Sprintf("%s\nno upcoming occurrences", header)
+	}
+
+	data := [][]string{{"date", "day"}}
+	for _, d := range ocr.Dates {
+		data = append(data, []string{d.String(), strings.ToLower(d.Weekday().String())})
+	}
+	footer := ""
+	if len(ocr.Dates) < ocr.N {
+		footer = "\nno more occurrences after this\n"
+	}
+
+	return fmt.Sprintf("%s\n\n%s%s", header, format.Table(data), footer)
+}
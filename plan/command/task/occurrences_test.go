@@ -0,0 +1,202 @@
+package task_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command/task"
+	"go-mod.ewintr.nl/planner/plan/storage/memory"
+)
+
+func TestRecurPreview(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		main        []string
+		fields      map[string]string
+		expParseErr bool
+		expDates    []item.Date
+	}{
+		{
+			name:        "wrong command",
+			main:        []string{"recur", "show", "2024-01-31, daily"},
+			expParseErr: true,
+		},
+		{
+			name:        "no recurrer",
+			main:        []string{"recur", "preview"},
+			expParseErr: true,
+		},
+		{
+			name:        "invalid recurrer",
+			main:        []string{"recur", "preview", "every now and then"},
+			expParseErr: true,
+		},
+		{
+			name:        "invalid n",
+			main:        []string{"recur", "preview", "2024-01-31, daily"},
+			fields:      map[string]string{"n": "none"},
+			expParseErr: true,
+		},
+		{
+			name:   "month end",
+			main:   []string{"recur", "preview", "2024-01-31, every 1 months"},
+			fields: map[string]string{"n": "3"},
+			expDates: []item.Date{
+				item.NewDate(2024, 1, 31),
+				item.NewDate(2024, 3, 2),
+				item.NewDate(2024, 3, 31),
+			},
+		},
+		{
+			name:   "unquoted",
+			main:   []string{"recur", "preview", "2024-01-31,", "every", "1", "months"},
+			fields: map[string]string{"n": "2"},
+			expDates: []item.Date{
+				item.NewDate(2024, 1, 31),
+				item.NewDate(2024, 3, 2),
+			},
+		},
+		{
+			name: "ending",
+			main: []string{"recur", "preview", "2024-01-31, daily, 3 times"},
+			expDates: []item.Date{
+				item.NewDate(2024, 1, 31),
+				item.NewDate(2024, 2, 1),
+				item.NewDate(2024, 2, 2),
+			},
+		},
+		{
+			name:   "except",
+			main:   []string{"recur", "preview", "2024-01-31, daily, except 2024-02-01"},
+			fields: map[string]string{"n": "2"},
+			expDates: []item.Date{
+				item.NewDate(2024, 1, 31),
+				item.NewDate(2024, 2, 2),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, actErr := task.NewRecurPreviewArgs().Parse(tc.main, tc.fields)
+			if tc.expParseErr != (actErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expParseErr, actErr)
+			}
+			if tc.expParseErr {
+				return
+			}
+
+			res, err := cmd.Do(nil, nil)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			actRes, ok := res.(task.OccurrencesResult)
+			if !ok {
+				t.Errorf("exp true, got false")
+			}
+			if diff := cmp.Diff(tc.expDates, actRes.Dates, cmp.AllowUnexported(item.Date{})); diff != "" {
+				t.Errorf("(exp +, got -)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	t.Parallel()
+
+	today := item.Today()
+	parent := item.Task{
+		ID:       "parent",
+		Recurrer: item.NewRecurrer(fmt.Sprintf("%s, daily, 3 times", today.Add(-1).String())),
+		TaskBody: item.TaskBody{Title: "daily"},
+	}
+	instance := item.Task{
+		ID:          item.InstanceID(parent.ID, today),
+		Date:        today,
+		RecurParent: parent.ID,
+		TaskBody:    item.TaskBody{Title: "daily"},
+	}
+	single := item.Task{
+		ID:       "single",
+		Date:     today,
+		TaskBody: item.TaskBody{Title: "single"},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		main        []string
+		fields      map[string]string
+		expParseErr bool
+		expDoErr    bool
+		expDates    []item.Date
+	}{
+		{
+			name:        "wrong command",
+			main:        []string{"1", "occurs"},
+			expParseErr: true,
+		},
+		{
+			name:        "no local id",
+			main:        []string{"occ", "one"},
+			expParseErr: true,
+		},
+		{
+			name:     "not recurring",
+			main:     []string{"3", "occurrences"},
+			expDoErr: true,
+		},
+		{
+			name:     "unknown local id",
+			main:     []string{"4", "occurrences"},
+			expDoErr: true,
+		},
+		{
+			name:     "recurring task",
+			main:     []string{"1", "occurrences"},
+			expDates: []item.Date{today, today.Add(1)},
+		},
+		{
+			name:     "instance",
+			main:     []string{"occ", "2"},
+			fields:   map[string]string{"n": "1"},
+			expDates: []item.Date{today},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mems := memory.New()
+			for i, tsk := range []item.Task{parent, instance, single} {
+				if err := mems.Task(nil).Store(tsk); err != nil {
+					t.Errorf("exp nil, got %v", err)
+				}
+				if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+					t.Errorf("exp nil, got %v", err)
+				}
+			}
+
+			cmd, actParseErr := task.NewOccurrencesArgs().Parse(tc.main, tc.fields)
+			if tc.expParseErr != (actParseErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expParseErr, actParseErr)
+			}
+			if tc.expParseErr {
+				return
+			}
+
+			res, actDoErr := cmd.Do(mems, nil)
+			if tc.expDoErr != (actDoErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expDoErr, actDoErr)
+			}
+			if tc.expDoErr {
+				return
+			}
+			actRes, ok := res.(task.OccurrencesResult)
+			if !ok {
+				t.Errorf("exp true, got false")
+			}
+			if diff := cmp.Diff(tc.expDates, actRes.Dates, cmp.AllowUnexported(item.Date{})); diff != "" {
+				t.Errorf("(exp +, got -)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/format"
+	"go-mod.ewintr.nl/planner/plan/storage"
+	"go-mod.ewintr.nl/planner/sync/client"
+)
+
+// DefaultDeadlineWindow is the number of days before a deadline in which it
+// is pointed out, if no other window is configured
+const DefaultDeadlineWindow = 3
+
+// formatDeadline shows the deadline, and whether it is within window days or
+// has passed. The deadline of a recurring task is that of its first
+// instance, so for the task itself it is never late or soon.
+func formatDeadline(tsk item.Task, today item.Date, window int) string {
+	switch {
+	case tsk.Deadline.IsZero():
+		return ""
+	case tsk.Recurrer != nil:
+		return tsk.Deadline.String()
+	case tsk.Overdue(today):
+		return fmt.Sprintf("%s late", tsk.Deadline.String())
+	case tsk.DeadlineWithin(today, window):
+		return fmt.Sprintf("%s soon", tsk.Deadline.String())
+	default:
+		return tsk.Deadline.String()
+	}
+}
+
+type OverdueArgs struct {
+	DeadlineWindow int
+}
+
+// NewOverdueArgs takes the number of days before a deadline in which a task
+// is due soon. Zero or less means the default.
+func NewOverdueArgs(deadlineWindow int) OverdueArgs {
+	if deadlineWindow <= 0 {
+		deadlineWindow = DefaultDeadlineWindow
+	}
+
+	return OverdueArgs{
+		DeadlineWindow: deadlineWindow,
+	}
+}
+
+func (oa OverdueArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
+	if len(main) != 1 || main[0] != "overdue" {
+		return nil, command.ErrWrongCommand
+	}
+
+	return Overdue{
+		Args: oa,
+	}, nil
+}
+
+// Overdue shows the tasks of which the deadline has passed, and those that
+// are due soon
+type Overdue struct {
+	Args OverdueArgs
+}
+
+func (o Overdue) Do(repos command.Repositories, _ client.Client) (command.CommandResult, error) {
+	tx, err := repos.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	localIDs, err := repos.LocalID(tx).FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not get local ids: %v", err)
+	}
+	all, err := repos.Task(tx).FindMany(storage.TaskListParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	today := item.Today()
+	overdue, soon := make([]TaskWithLID, 0), make([]TaskWithLID, 0)
+	for _, tsk := range all {
+		// the instances of a recurring task have their own deadline
+		if tsk.Recurrer != nil {
+			continue
+		}
+		isOverdue := tsk.Overdue(today)
+		if !isOverdue && !tsk.DeadlineWithin(today, o.Args.DeadlineWindow) {
+			continue
+		}
+		lid, ok := localIDs[tsk.ID]
+		if !ok {
+			return nil, fmt.Errorf("could not find local id for %s", tsk.ID)
+		}
+		tl := TaskWithLID{
+			LocalID:       lid,
+			ParentLocalID: localIDs[tsk.Parent],
+			Task:          tsk,
+		}
+		if isOverdue {
+			overdue = append(overdue, tl)
+		} else {
+			soon = append(soon, tl)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not list overdue tasks: %v", err)
+	}
+
+	return OverdueResult{
+		Tasks: overdue,
+		Soon:  soon,
+		Today: today,
+	}, nil
+}
+
+type OverdueResult struct {
+	Tasks []TaskWithLID
+	Soon  []TaskWithLID
+	Today item.Date
+}
+
+func (odr OverdueResult) Render() string {
+	if len(odr.Tasks) == 0 && len(odr.Soon) == 0 {
+		return "\nno overdue tasks\n"
+	}
+
+	var out string
+	if len(odr.Tasks) > 0 {
+		data := [][]string{{"id", "project", "deadline", "late", "title"}}
+		for _, tl := range sortByDeadline(odr.Tasks) {
+			data = append(data, []string{
+				tl.localRef(),
+				tl.Task.Project,
+				tl.Task.Deadline.String(),
+				fmt.Sprintf("%dd", odr.Today.DaysBetween(tl.Task.Deadline)),
+				tl.Task.Title,
+			})
+		}
+		out += fmt.Sprintf("\n%s\n", format.Table(data))
+	}
+	if len(odr.Soon) > 0 {
+		data := [][]string{{"id", "project", "deadline", "left", "title"}}
+		for _, tl := range sortByDeadline(odr.Soon) {
+			data = append(data, []string{
+				tl.localRef(),
+				tl.Task.Project,
+				tl.Task.Deadline.String(),
+				fmt.Sprintf("%dd", odr.Today.DaysBetween(tl.Task.Deadline)),
+				tl.Task.Title,
+			})
+		}
+		out += fmt.Sprintf("\ndue soon:\n\n%s\n", format.Table(data))
+	}
+
+	return out
+}
+
+// sortByDeadline sorts the tasks by deadline, the earliest first
+func sortByDeadline(tasks []TaskWithLID) []TaskWithLID {
+	sort.Slice(tasks, func(i, j int) bool {
+		di, dj := tasks[i].Task.Deadline, tasks[j].Task.Deadline
+		if !di.Equal(dj) {
+			return dj.After(di)
+		}
+		return tasks[i].LocalID < tasks[j].LocalID
+	})
+
+	return tasks
+}
@@ -0,0 +1,76 @@
+package task_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command/task"
+	"go-mod.ewintr.nl/planner/plan/storage/memory"
+)
+
+func TestOverdue(t *testing.T) {
+	t.Parallel()
+
+	today := item.Today()
+	mems := memory.New()
+	for i, tsk := range []item.Task{
+		{ID: "a", TaskBody: item.TaskBody{Title: "no deadline"}},
+		{ID: "b", Date: today.Add(-5), TaskBody: item.TaskBody{Title: "planned before"}},
+		{ID: "c", TaskBody: item.TaskBody{Title: "today", Deadline: today}},
+		{ID: "d", TaskBody: item.TaskBody{Title: "passed", Deadline: today.Add(-1)}},
+		{ID: "e", Date: today.Add(2), TaskBody: item.TaskBody{Title: "long passed", Deadline: today.Add(-10)}},
+		{ID: "f", TaskBody: item.TaskBody{Title: "soon", Deadline: today.Add(2)}},
+		{ID: "g", TaskBody: item.TaskBody{Title: "later", Deadline: today.Add(5)}},
+		{ID: "h", Recurrer: item.Daily{Start: today.Add(-3)}, TaskBody: item.TaskBody{Title: "recurring passed", Deadline: today.Add(-2)}},
+		{ID: "i", Recurrer: item.Daily{Start: today}, TaskBody: item.TaskBody{Title: "recurring soon", Deadline: today.Add(1)}},
+	} {
+		if err := mems.Task(nil).Store(tsk); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		window  int
+		expSoon map[string]bool
+	}{
+		{
+			name:    "default",
+			expSoon: map[string]bool{"today": true, "soon": true},
+		},
+		{
+			name:    "configured",
+			window:  5,
+			expSoon: map[string]bool{"today": true, "soon": true, "later": true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := task.NewOverdueArgs(tc.window).Parse([]string{"overdue"}, nil)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			res, err := cmd.Do(mems, nil)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			act := make(map[string]bool)
+			for _, tl := range res.(task.OverdueResult).Tasks {
+				act[tl.Task.Title] = true
+			}
+			if diff := cmp.Diff(map[string]bool{"passed": true, "long passed": true}, act); diff != "" {
+				t.Errorf("(exp -, got +)\n%s", diff)
+			}
+			actSoon := make(map[string]bool)
+			for _, tl := range res.(task.OverdueResult).Soon {
+				actSoon[tl.Task.Title] = true
+			}
+			if diff := cmp.Diff(tc.expSoon, actSoon); diff != "" {
+				t.Errorf("(exp -, got +)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package task
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/cli/arg"
+	"go-mod.ewintr.nl/planner/plan/command"
+)
+
+const (
+	maxDateWords  = 4
+	maxRecurWords = 10
+)
+
+// words that parse as a date, but are more likely part of a title
+var ambiguousDates = []string{"tom", "tod"}
+
+// the kinds quickAdd picks out of a title, besides tags
+var quickKinds = []string{"date", "time", "project", "duration", "recurrer"}
+
+// QuickAddArgs adds a task like add does, but also picks the date, time,
+// project, duration and recurrence out of the title.
+type QuickAddArgs struct {
+	add AddArgs
+}
+
+func NewQuickAddArgs() QuickAddArgs {
+	return QuickAddArgs{
+		add: NewAddArgs(),
+	}
+}
+
+// ParseRaw splits off the fields itself, because here words that start
+// with a digit, like 18:00, are part of the title and not fields.
+func (qaa QuickAddArgs) ParseRaw(args []string) (command.Command, error) {
+	main := make([]string, 0, len(args))
+	fields := make(map[string]string)
+	for _, a := range args {
+		if a != "" && unicode.IsDigit(rune(a[0])) {
+			main = append(main, a)
+			continue
+		}
+		m, f := arg.FindFields([]string{a})
+		main = append(main, m...)
+		maps.Copy(fields, f)
+	}
+
+	return qaa.Parse(main, fields)
+}
+
+// Parse reads "qa call mom tomorrow 18:00 +family for 30m every sunday".
+// Fields can be used as with add, and win over what is found in the title.
+func (qaa QuickAddArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
+	if len(main) == 0 || !slices.Contains([]string{"qa", "quick"}, main[0]) {
+		return nil, command.ErrWrongCommand
+	}
+
+	return qaa.add.parse(main[1:], fields, true)
+}
+
+// quickAdd holds what was recognized in the words of a title
+type quickAdd struct {
+	Title    string
+	Date     item.Date
+	Time     item.Time
+	Project  string
+	Duration time.Duration
+	Tags     []string
+	// Recurrer is the phrase, it can only be parsed when the date is known
+	Recurrer string
+	// Parsed lists the kinds that were recognized, in order of appearance
+	Parsed []string
+}
+
+// parseQuickAdd picks a date, a time, a project, tags, a duration and a
+// recurrence out of the words of a title, as in "call mom tomorrow 18:00
+// +family @phone for 30m every sunday". Only the first of each kind is used,
+// except for tags, and kinds that are in skip are left alone. Words that
+// start with a backslash, or that contain a space because they were quoted,
+// are always part of the title.
+//
+// A date is only taken from the end of the title, or from the start if it
+// is a full date or follows "on", as in "on friday call mom". Elsewhere, like
+// in "today is a good day", it is part of the title.
+func parseQuickAdd(words []string, ref item.Date, skip []string) quickAdd {
+	qa := quickAdd{}
+	title := make([]string, 0, len(words))
+	wants := func(kind string) bool {
+		return !slices.Contains(skip, kind) && !slices.Contains(qa.Parsed, kind)
+	}
+	// the words of a date that only counts if no more title follows
+	var dateWords []string
+	addTitle := func(word string) {
+		if dateWords != nil {
+			title = append(title, dateWords...)
+			dateWords = nil
+			qa.Date = item.Date{}
+			qa.Parsed = slices.DeleteFunc(qa.Parsed, func(kind string) bool { return kind == "date" })
+			if len(qa.Parsed) == 0 {
+				qa.Parsed = nil
+			}
+		}
+		title = append(title, word)
+	}
+
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if isLiteral(word) {
+			addTitle(strings.TrimPrefix(word, `\`))
+			continue
+		}
+		lower := strings.ToLower(word)
+		next := ""
+		if i+1 < len(words) && !isLiteral(words[i+1]) {
+			next = words[i+1]
+		}
+
+		if wants("recurrer") && lower == "every" {
+			if phrase, n := findRecurrer(words[i:], ref); n > 0 {
+				qa.Recurrer = phrase
+				qa.Parsed = append(qa.Parsed, "recurrer")
+				i += n - 1
+				continue
+			}
+		}
+		if wants("duration") && lower == "for" && next != "" {
+			if d, err := time.ParseDuration(next); err == nil && d > 0 {
+				qa.Duration = d
+				qa.Parsed = append(qa.Parsed, "duration")
+				i++
+				continue
+			}
+		}
+		if wants("time") {
+			if t := item.NewTimeFromString(word); !t.IsZero() {
+				qa.Time = t
+				qa.Parsed = append(qa.Parsed, "time")
+				continue
+			}
+			if lower == "at" && next != "" {
+				if t := item.NewTimeFromString(next); !t.IsZero() {
+					qa.Time = t
+					qa.Parsed = append(qa.Parsed, "time")
+					i++
+					continue
+				}
+			}
+		}
+		if wants("date") {
+			start := i
+			if lower == "on" {
+				start++
+			}
+			if d, n := findDate(words[start:], ref, start > i); n > 0 {
+				qa.Date = d
+				qa.Parsed = append(qa.Parsed, "date")
+				if len(title) > 0 || (start == i && !isFullDate(words[i])) {
+					dateWords = words[i : start+n]
+				}
+				i = start + n - 1
+				continue
+			}
+		}
+		// there can be more than one tag
+		if !slices.Contains(skip, "tags") && len(word) > 1 && strings.HasPrefix(word, "@") {
+			qa.Tags = append(qa.Tags, word)
+			if !slices.Contains(qa.Parsed, "tags") {
+				qa.Parsed = append(qa.Parsed, "tags")
+			}
+			continue
+		}
+		if wants("project") && len(word) > 1 && strings.HasPrefix(word, "+") {
+			qa.Project = word[1:]
+			qa.Parsed = append(qa.Parsed, "project")
+			continue
+		}
+
+		addTitle(word)
+	}
+	qa.Title = strings.Join(title, " ")
+
+	return qa
+}
+
+func isLiteral(word string) bool {
+	return strings.HasPrefix(word, `\`) || strings.Contains(word, " ")
+}
+
+// findDate returns the date in the longest phrase at the start of words
+// that is one, and the number of words in that phrase. Unless the phrase was
+// introduced with "on", abbreviated weekdays and dates like 24/12 are not
+// accepted, as in "call mom sun" or "24/7 monitoring" they are likely part of
+// the title.
+func findDate(words []string, ref item.Date, explicit bool) (item.Date, int) {
+	if len(words) == 0 || isLiteral(words[0]) || slices.Contains(ambiguousDates, strings.ToLower(words[0])) {
+		return item.Date{}, 0
+	}
+	vague := func(word string) bool {
+		if explicit {
+			return false
+		}
+		word = strings.ToLower(word)
+		if strings.Contains(word, "/") {
+			return true
+		}
+		wd, ok := item.ParseWeekday(word)
+
+		return ok && word != strings.ToLower(wd.String())
+	}
+	// a full date needs nothing after it, and would ignore it anyway
+	if isFullDate(words[0]) {
+		return item.ParseDate(words[0], ref), 1
+	}
+	for n := min(len(words), maxDateWords); n > 0; n-- {
+		if slices.ContainsFunc(words[1:n], isLiteral) || slices.ContainsFunc(words[:n], vague) {
+			continue
+		}
+		if d := item.ParseDate(strings.Join(words[:n], " "), ref); !d.IsZero() {
+			return d, n
+		}
+	}
+
+	return item.Date{}, 0
+}
+
+func isFullDate(word string) bool {
+	_, err := time.Parse(item.DateFormat, word)
+	return err == nil
+}
+
+// findRecurrer returns the longest phrase at the start of words that is a
+// recurrer, and the number of words in that phrase
+func findRecurrer(words []string, ref item.Date) (string, int) {
+	for n := min(len(words), maxRecurWords); n > 1; n-- {
+		if slices.ContainsFunc(words[1:n], isLiteral) {
+			continue
+		}
+		phrase := strings.Join(words[:n], " ")
+		if item.ParseRecurrer(phrase, ref) != nil {
+			return phrase, n
+		}
+	}
+
+	return "", 0
+}
@@ -0,0 +1,299 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command/task"
+)
+
+func TestQuickAdd(t *testing.T) {
+	t.Parallel()
+
+	today := item.Today()
+	tomorrow := today.Add(1)
+	friday := item.ParseDate("friday", today)
+	sundays := item.ParseRecurrer("every sunday", tomorrow)
+
+	for _, tc := range []struct {
+		name      string
+		main      []string
+		fields    map[string]string
+		expErr    bool
+		expTask   item.Task
+		expParsed []string
+	}{
+		{
+			name: "plain",
+			main: []string{"qa", "buy", "bread"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{Title: "buy bread"},
+			},
+		},
+		{
+			name: "all",
+			main: []string{"qa", "call", "mom", "tomorrow", "18:00", "+family", "for", "30m", "every", "sunday"},
+			expTask: item.Task{
+				Date:      tomorrow,
+				Recurrer:  sundays,
+				RecurNext: sundays.First(),
+				TaskBody: item.TaskBody{
+					Title:    "call mom",
+					Project:  "family",
+					Time:     item.NewTime(18, 0),
+					Duration: 30 * time.Minute,
+				},
+			},
+			expParsed: []string{"date", "time", "project", "duration", "recurrer"},
+		},
+		{
+			name: "tags",
+			main: []string{"qa", "call", "@phone", "bob", "@Home"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{
+					Title: "call bob",
+					Tags:  []string{"home", "phone"},
+				},
+			},
+			expParsed: []string{"tags"},
+		},
+		{
+			name: "on and at",
+			main: []string{"qa", "meet", "on", "friday", "at", "9:30"},
+			expTask: item.Task{
+				Date: friday,
+				TaskBody: item.TaskBody{
+					Title: "meet",
+					Time:  item.NewTime(9, 30),
+				},
+			},
+			expParsed: []string{"date", "time"},
+		},
+		{
+			name: "phrase",
+			main: []string{"qa", "pay", "rent", "end", "of", "month"},
+			expTask: item.Task{
+				Date:     item.ParseDate("end of month", today),
+				TaskBody: item.TaskBody{Title: "pay rent"},
+			},
+			expParsed: []string{"date"},
+		},
+		{
+			name: "short offset is no project",
+			main: []string{"qa", "review", "+2w"},
+			expTask: item.Task{
+				Date:     today.Add(14),
+				TaskBody: item.TaskBody{Title: "review"},
+			},
+			expParsed: []string{"date"},
+		},
+		{
+			name: "full date",
+			main: []string{"qa", "2024-01-01", "report"},
+			expTask: item.Task{
+				Date:     item.NewDate(2024, 1, 1),
+				TaskBody: item.TaskBody{Title: "report"},
+			},
+			expParsed: []string{"date"},
+		},
+		{
+			name: "only at the end",
+			main: []string{"qa", "move", "monday", "to", "friday"},
+			expTask: item.Task{
+				Date:     friday,
+				TaskBody: item.TaskBody{Title: "move monday to"},
+			},
+			expParsed: []string{"date"},
+		},
+		{
+			name: "start of title",
+			main: []string{"qa", "today", "is", "a", "good", "day"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{Title: "today is a good day"},
+			},
+		},
+		{
+			name: "start with on",
+			main: []string{"qa", "on", "friday", "call", "mom"},
+			expTask: item.Task{
+				Date:     friday,
+				TaskBody: item.TaskBody{Title: "call mom"},
+			},
+			expParsed: []string{"date"},
+		},
+		{
+			name: "numbers at the end",
+			main: []string{"qa", "fix", "bug", "1", "2"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{Title: "fix bug 1 2"},
+			},
+		},
+		{
+			name: "numbers in title",
+			main: []string{"qa", "review", "3", "4", "pages"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{Title: "review 3 4 pages"},
+			},
+		},
+		{
+			name: "episode",
+			main: []string{"qa", "watch", "season", "1", "12"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{Title: "watch season 1 12"},
+			},
+		},
+		{
+			name: "no duration",
+			main: []string{"qa", "shop", "for", "food"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{Title: "shop for food"},
+			},
+		},
+		{
+			name: "name",
+			main: []string{"qa", "call", "tom"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{Title: "call tom"},
+			},
+		},
+		{
+			name: "escaped",
+			main: []string{"qa", "watch", `\tomorrow`, "never", "dies"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{Title: "watch tomorrow never dies"},
+			},
+		},
+		{
+			name: "quoted",
+			main: []string{"qa", "read next week", "next", "week"},
+			expTask: item.Task{
+				Date:     item.ParseDate("next week", today),
+				TaskBody: item.TaskBody{Title: "read next week"},
+			},
+			expParsed: []string{"date"},
+		},
+		{
+			name:   "field wins",
+			main:   []string{"qa", "meet", "monday"},
+			fields: map[string]string{"date": "friday"},
+			expTask: item.Task{
+				Date:     friday,
+				TaskBody: item.TaskBody{Title: "meet monday"},
+			},
+		},
+		{
+			name: "abbreviated weekday",
+			main: []string{"qa", "call", "mom", "sun"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{Title: "call mom sun"},
+			},
+		},
+		{
+			name: "abbreviated weekday in title",
+			main: []string{"qa", "fix", "wed", "deploy"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{Title: "fix wed deploy"},
+			},
+		},
+		{
+			name: "fraction",
+			main: []string{"qa", "ship", "24/7", "monitoring"},
+			expTask: item.Task{
+				TaskBody: item.TaskBody{Title: "ship 24/7 monitoring"},
+			},
+		},
+		{
+			name: "full weekday",
+			main: []string{"qa", "call", "mom", "sunday"},
+			expTask: item.Task{
+				Date:     item.ParseDate("sunday", today),
+				TaskBody: item.TaskBody{Title: "call mom"},
+			},
+			expParsed: []string{"date"},
+		},
+		{
+			name: "explicit abbreviation",
+			main: []string{"qa", "call", "mom", "on", "sun"},
+			expTask: item.Task{
+				Date:     item.ParseDate("sunday", today),
+				TaskBody: item.TaskBody{Title: "call mom"},
+			},
+			expParsed: []string{"date"},
+		},
+		{
+			name:   "nothing left",
+			main:   []string{"qa", "tomorrow"},
+			expErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, actErr := task.NewQuickAddArgs().Parse(tc.main, tc.fields)
+			if tc.expErr != (actErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expErr, actErr)
+			}
+			if tc.expErr {
+				return
+			}
+			addCmd, ok := cmd.(*task.Add)
+			if !ok {
+				t.Errorf("exp true, got false")
+			}
+			tc.expTask.ID = addCmd.Args.Task.ID
+			tc.expTask.Created = today
+			if diff := item.TaskDiff(tc.expTask, addCmd.Args.Task); diff != "" {
+				t.Errorf("(exp -, got +)\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expParsed, addCmd.Args.Parsed); diff != "" {
+				t.Errorf("(exp -, got +)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuickAddRaw(t *testing.T) {
+	t.Parallel()
+
+	cmd, err := task.NewQuickAddArgs().ParseRaw([]string{"qa", "call", "mom", "at", "18:00", "p:family"})
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	addCmd, ok := cmd.(*task.Add)
+	if !ok {
+		t.Errorf("exp true, got false")
+	}
+	if addCmd.Args.Task.Title != "call mom" {
+		t.Errorf("exp call mom, got %v", addCmd.Args.Task.Title)
+	}
+	if exp := item.NewTime(18, 0); addCmd.Args.Task.Time.String() != exp.String() {
+		t.Errorf("exp %v, got %v", exp.String(), addCmd.Args.Task.Time.String())
+	}
+	if addCmd.Args.Task.Project != "family" {
+		t.Errorf("exp family, got %v", addCmd.Args.Task.Project)
+	}
+}
+
+func TestAddNotQuick(t *testing.T) {
+	t.Parallel()
+
+	cmd, err := task.NewAddArgs().Parse([]string{"add", "call", "mom", "sunday", "+family", "@phone"}, nil)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	addCmd, ok := cmd.(*task.Add)
+	if !ok {
+		t.Errorf("exp true, got false")
+	}
+	exp := item.Task{
+		ID: addCmd.Args.Task.ID,
+		TaskBody: item.TaskBody{
+			Title:   "call mom sunday +family",
+			Created: item.Today(),
+			Tags:    []string{"phone"},
+		},
+	}
+	if diff := item.TaskDiff(exp, addCmd.Args.Task); diff != "" {
+		t.Errorf("(exp -, got +)\n%s", diff)
+	}
+}
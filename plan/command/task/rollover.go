@@ -0,0 +1,108 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/format"
+	"go-mod.ewintr.nl/planner/plan/storage"
+	"go-mod.ewintr.nl/planner/sync/client"
+)
+
+type RolloverArgs struct{}
+
+func NewRolloverArgs() RolloverArgs {
+	return RolloverArgs{}
+}
+
+func (ra RolloverArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
+	if len(main) != 1 || main[0] != "rollover" {
+		return nil, command.ErrWrongCommand
+	}
+
+	return Rollover{}, nil
+}
+
+// Rollover moves the tasks that were planned before today, and are not done,
+// to today. Recurring tasks are left alone, a missed instance is not the
+// same as a postponed task. So are snoozed tasks, they come back on the day
+// they were snoozed until. A task counts as postponed once a day at most.
+type Rollover struct{}
+
+func (r Rollover) Do(repos command.Repositories, _ client.Client) (command.CommandResult, error) {
+	tx, err := repos.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	localIDs, err := repos.LocalID(tx).FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not get local ids: %v", err)
+	}
+	today := item.Today()
+	past, err := repos.Task(tx).FindMany(storage.TaskListParams{
+		HasDate: true,
+		To:      today.Add(-1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	moved := make([]TaskWithLID, 0, len(past))
+	for _, tsk := range past {
+		if tsk.Recurrer != nil || tsk.RecurParent != "" || tsk.Hidden(today) {
+			continue
+		}
+		tsk.Date = today
+		if !tsk.RolledOver.Equal(today) {
+			tsk.Postponed++
+			tsk.RolledOver = today
+		}
+		if err := repos.Task(tx).Store(tsk); err != nil {
+			return nil, fmt.Errorf("could not store task: %v", err)
+		}
+		it, err := tsk.Item()
+		if err != nil {
+			return nil, fmt.Errorf("could not convert task to sync item: %v", err)
+		}
+		if err := repos.Sync(tx).Store(it); err != nil {
+			return nil, fmt.Errorf("could not store sync item: %v", err)
+		}
+		moved = append(moved, TaskWithLID{
+			LocalID:       localIDs[tsk.ID],
+			ParentLocalID: localIDs[tsk.Parent],
+			Task:          tsk,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not roll over tasks: %v", err)
+	}
+
+	return RolloverResult{
+		Tasks: moved,
+	}, nil
+}
+
+type RolloverResult struct {
+	Tasks []TaskWithLID
+}
+
+func (rr RolloverResult) Render() string {
+	if len(rr.Tasks) == 0 {
+		return "no tasks to roll over"
+	}
+
+	sort.Slice(rr.Tasks, func(i, j int) bool {
+		return rr.Tasks[i].LocalID < rr.Tasks[j].LocalID
+	})
+	data := [][]string{{"id", "post", "title"}}
+	for _, tl := range rr.Tasks {
+		data = append(data, []string{tl.localRef(), fmt.Sprintf("%d", tl.Task.Postponed), tl.Task.Title})
+	}
+
+	return fmt.Sprintf("moved %d tasks to today\n\n%s", len(rr.Tasks), format.Table(data))
+}
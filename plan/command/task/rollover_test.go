@@ -0,0 +1,82 @@
+package task_test
+
+import (
+	"testing"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command/task"
+	"go-mod.ewintr.nl/planner/plan/storage/memory"
+)
+
+func TestRollover(t *testing.T) {
+	t.Parallel()
+
+	today := item.Today()
+	yesterday := today.Add(-1)
+	mems := memory.New()
+	for i, tsk := range []item.Task{
+		{ID: "past", Date: yesterday, TaskBody: item.TaskBody{Title: "past"}},
+		{ID: "again", Date: today.Add(-3), TaskBody: item.TaskBody{Title: "again", Postponed: 2}},
+		{ID: "today", Date: today, TaskBody: item.TaskBody{Title: "today"}},
+		{ID: "undated", TaskBody: item.TaskBody{Title: "undated"}},
+		{ID: "recurring", Date: yesterday, Recurrer: item.NewRecurrer(yesterday.String() + ", daily"), TaskBody: item.TaskBody{Title: "recurring"}},
+		{ID: "instance", Date: yesterday, RecurParent: "recurring", TaskBody: item.TaskBody{Title: "instance"}},
+		{ID: "snoozed", Date: yesterday, TaskBody: item.TaskBody{Title: "snoozed", SnoozeUntil: today.Add(7)}},
+		{ID: "counted", Date: yesterday, TaskBody: item.TaskBody{Title: "counted", Postponed: 1, RolledOver: today}},
+	} {
+		if err := mems.Task(nil).Store(tsk); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+	}
+
+	cmd, err := task.NewRolloverArgs().Parse([]string{"rollover"}, nil)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	res, err := cmd.Do(mems, nil)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if act := len(res.(task.RolloverResult).Tasks); act != 3 {
+		t.Errorf("exp 3, got %v", act)
+	}
+
+	for _, tc := range []struct {
+		id           string
+		expDate      item.Date
+		expPostponed int
+	}{
+		{id: "past", expDate: today, expPostponed: 1},
+		{id: "again", expDate: today, expPostponed: 3},
+		{id: "today", expDate: today},
+		{id: "undated"},
+		{id: "recurring", expDate: yesterday},
+		{id: "instance", expDate: yesterday},
+		{id: "snoozed", expDate: yesterday},
+		{id: "counted", expDate: today, expPostponed: 1},
+	} {
+		t.Run(tc.id, func(t *testing.T) {
+			act, err := mems.Task(nil).FindOne(tc.id)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if !act.Date.Equal(tc.expDate) {
+				t.Errorf("exp %v, got %v", tc.expDate, act.Date)
+			}
+			if act.Postponed != tc.expPostponed {
+				t.Errorf("exp %v, got %v", tc.expPostponed, act.Postponed)
+			}
+		})
+	}
+
+	updated, err := mems.Sync(nil).FindAll()
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if len(updated) != 3 {
+		t.Errorf("exp 3, got %v", len(updated))
+	}
+}
@@ -0,0 +1,76 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/storage"
+)
+
+// Scope tells which part of a recurring series an update or a delete
+// applies to
+type Scope string
+
+const (
+	ScopeThis   Scope = "this"
+	ScopeFuture Scope = "future"
+	ScopeAll    Scope = "all"
+)
+
+func ParseScope(s string) (Scope, error) {
+	switch sc := Scope(s); sc {
+	case ScopeThis, ScopeFuture, ScopeAll:
+		return sc, nil
+	default:
+		return "", fmt.Errorf("%w: scope must be this, future or all", command.ErrInvalidArg)
+	}
+}
+
+// findSeries returns the recurring task that tsk is part of. That is the
+// parent for an instance, or tsk itself when it is the recurring task.
+func findSeries(repo storage.Task, tsk item.Task) (item.Task, error) {
+	if tsk.RecurParent == "" {
+		if tsk.Recurrer == nil {
+			return item.Task{}, fmt.Errorf("task is not recurring")
+		}
+		return tsk, nil
+	}
+
+	parent, err := repo.FindOne(tsk.RecurParent)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return item.Task{}, fmt.Errorf("could not find recurring task of this instance")
+	case err != nil:
+		return item.Task{}, fmt.Errorf("could not get recurring task: %v", err)
+	case parent.Recurrer == nil:
+		return item.Task{}, fmt.Errorf("task is not recurring anymore")
+	}
+
+	return parent, nil
+}
+
+// seriesFrom returns the date of the first instance that the scope covers
+func seriesFrom(scope Scope, tsk, parent item.Task) item.Date {
+	switch {
+	case scope == ScopeAll:
+		return parent.Recurrer.First()
+	case tsk.ID == parent.ID:
+		return item.Today()
+	default:
+		return tsk.Date
+	}
+}
+
+// deleteLocal removes a task that the server will delete, or already has
+func deleteLocal(repos command.Repositories, tx *storage.Tx, id string) error {
+	if err := repos.LocalID(tx).Delete(id); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("could not delete local id: %v", err)
+	}
+	if err := repos.Task(tx).Delete(id); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("could not delete task: %v", err)
+	}
+
+	return nil
+}
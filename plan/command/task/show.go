@@ -3,7 +3,6 @@ package task
 import (
 	"errors"
 	"fmt"
-	"strconv"
 
 	"go-mod.ewintr.nl/planner/item"
 	"go-mod.ewintr.nl/planner/plan/command"
@@ -13,25 +12,37 @@ import (
 )
 
 type ShowArgs struct {
-	localID int
+	localID        int
+	sub            int
+	deadlineWindow int
 }
 
-func NewShowArgs() ShowArgs {
-	return ShowArgs{}
+// NewShowArgs takes the number of days before a deadline in which it is
+// pointed out. Zero or less means the default.
+func NewShowArgs(deadlineWindow int) ShowArgs {
+	if deadlineWindow <= 0 {
+		deadlineWindow = DefaultDeadlineWindow
+	}
+
+	return ShowArgs{
+		deadlineWindow: deadlineWindow,
+	}
 }
 
 func (sa ShowArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
 	if len(main) != 1 {
 		return nil, command.ErrWrongCommand
 	}
-	lid, err := strconv.Atoi(main[0])
+	lid, sub, err := parseLocalRef(main[0])
 	if err != nil {
 		return nil, command.ErrWrongCommand
 	}
 
 	return &Show{
 		args: ShowArgs{
-			localID: lid,
+			localID:        lid,
+			sub:            sub,
+			deadlineWindow: sa.deadlineWindow,
 		},
 	}, nil
 }
@@ -47,17 +58,61 @@ func (s Show) Do(repos command.Repositories, _ client.Client) (command.CommandRe
 	}
 	defer tx.Rollback()
 
-	id, err := repos.LocalID(tx).FindOne(s.args.localID)
-	switch {
-	case errors.Is(err, storage.ErrNotFound):
-		return nil, fmt.Errorf("could not find local id")
-	case err != nil:
+	tsk, err := findByLocalRef(repos, tx, s.args.localID, s.args.sub)
+	if err != nil {
 		return nil, err
 	}
 
-	tsk, err := repos.Task(tx).FindOne(id)
+	localIDs, err := repos.LocalID(tx).FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not get local ids: %v", err)
+	}
+	var parent *TaskWithLID
+	if tsk.Parent != "" {
+		p, err := repos.Task(tx).FindOne(tsk.Parent)
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			// parent was deleted or is not synced
+		case err != nil:
+			return nil, fmt.Errorf("could not find parent: %v", err)
+		default:
+			parent = &TaskWithLID{
+				LocalID: localIDs[p.ID],
+				Task:    p,
+			}
+		}
+	}
+	subtasks, err := findSubtasks(repos.Task(tx), tsk.ID)
 	if err != nil {
-		return nil, fmt.Errorf("could not find task")
+		return nil, err
+	}
+	blockers, err := openBlockers(repos.Task(tx), tsk)
+	if err != nil {
+		return nil, err
+	}
+	blockedBy := make([]TaskWithLID, 0, len(blockers))
+	for _, b := range blockers {
+		blockedBy = append(blockedBy, TaskWithLID{
+			LocalID:       localIDs[b.ID],
+			ParentLocalID: localIDs[b.Parent],
+			Task:          b,
+		})
+	}
+
+	var origin *TaskWithLID
+	if tsk.RecurParent != "" {
+		parent, err := repos.Task(tx).FindOne(tsk.RecurParent)
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			// parent was deleted or is not synced
+		case err != nil:
+			return nil, fmt.Errorf("could not find recurring parent: %v", err)
+		default:
+			origin = &TaskWithLID{
+				LocalID: localIDs[parent.ID],
+				Task:    parent,
+			}
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -65,14 +120,25 @@ func (s Show) Do(repos command.Repositories, _ client.Client) (command.CommandRe
 	}
 
 	return ShowResult{
-		LocalID: s.args.localID,
-		Task:    tsk,
+		LocalID:        localIDs[tsk.ID],
+		Task:           tsk,
+		Origin:         origin,
+		Parent:         parent,
+		Subtasks:       subtasks,
+		BlockedBy:      blockedBy,
+		DeadlineWindow: s.args.deadlineWindow,
 	}, nil
 }
 
 type ShowResult struct {
-	LocalID int
-	Task    item.Task
+	LocalID  int
+	Task     item.Task
+	Origin   *TaskWithLID
+	Parent   *TaskWithLID
+	Subtasks []item.Task
+	// BlockedBy are the tasks that must be done first
+	BlockedBy      []TaskWithLID
+	DeadlineWindow int
 }
 
 func (sr ShowResult) Render() string {
@@ -83,14 +149,64 @@ func (sr ShowResult) Render() string {
 	}
 	data := [][]string{
 		{"title", sr.Task.Title},
-		{"local id", fmt.Sprintf("%d", sr.LocalID)},
+		{"local id", sr.localRef()},
 		{"project", sr.Task.Project},
 		{"date", sr.Task.Date.String()},
+		{"deadline", formatDeadline(sr.Task, item.Today(), sr.DeadlineWindow)},
 		{"time", sr.Task.Time.String()},
 		{"duration", sr.Task.Duration.String()},
+		{"priority", sr.Task.Priority.String()},
+		{"tags", formatTags(sr.Task.Tags)},
 		{"recur", recurStr},
 		// {"id", s.Task.ID},
 	}
+	switch {
+	case sr.Origin != nil:
+		data = append(data, []string{"origin", fmt.Sprintf("%d: %s", sr.Origin.LocalID, sr.Origin.Task.Title)})
+	case sr.Task.RecurParent != "":
+		data = append(data, []string{"origin", "unknown recurring task"})
+	}
+	if sr.Task.Hidden(item.Today()) {
+		data = append(data, []string{"snoozed", fmt.Sprintf("until %s", sr.Task.SnoozeUntil.String())})
+	}
+	if sr.Task.Postponed > 0 {
+		data = append(data, []string{"postponed", fmt.Sprintf("%d times", sr.Task.Postponed)})
+	}
+	switch {
+	case sr.Parent != nil:
+		data = append(data, []string{"parent", fmt.Sprintf("%d: %s", sr.Parent.LocalID, sr.Parent.Task.Title)})
+	case sr.Task.Parent != "":
+		data = append(data, []string{"parent", "unknown task"})
+	}
+	for i, b := range sr.BlockedBy {
+		key := ""
+		if i == 0 {
+			key = "blocked by"
+		}
+		data = append(data, []string{key, fmt.Sprintf("%s: %s", b.localRef(), b.Task.Title)})
+	}
+
+	res := fmt.Sprintf("\n%s\n", format.Table(data))
+	if len(sr.Subtasks) > 0 {
+		subData := [][]string{{"id", "subtasks"}}
+		for _, st := range sr.Subtasks {
+			subData = append(subData, []string{formatLocalRef(sr.LocalID, st.Position), st.Title})
+		}
+		res += fmt.Sprintf("%s\n", format.Table(subData))
+	}
+	if sr.Task.Notes != "" {
+		res += fmt.Sprintf("%s\n", sr.Task.Notes)
+	}
+
+	return res
+}
+
+// localRef is the subtask reference for a subtask, if the parent is known,
+// and the local id otherwise
+func (sr ShowResult) localRef() string {
+	if sr.Parent != nil {
+		return formatLocalRef(sr.Parent.LocalID, sr.Task.Position)
+	}
 
-	return fmt.Sprintf("\n%s\n", format.Table(data))
+	return fmt.Sprintf("%d", sr.LocalID)
 }
@@ -60,7 +60,7 @@ func TestShow(t *testing.T) {
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			// parse
-			cmd, actParseErr := task.NewShowArgs().Parse(tc.main, nil)
+			cmd, actParseErr := task.NewShowArgs(0).Parse(tc.main, nil)
 			if tc.expParseErr != (actParseErr != nil) {
 				t.Errorf("exp %v, got %v", tc.expParseErr, actParseErr != nil)
 			}
@@ -83,3 +83,79 @@ func TestShow(t *testing.T) {
 	}
 
 }
+
+func TestShowOrigin(t *testing.T) {
+	t.Parallel()
+
+	mems := memory.New()
+	parent := item.Task{
+		ID:       "parent",
+		Recurrer: item.NewRecurrer("2024-10-01, daily"),
+		TaskBody: item.TaskBody{
+			Title: "water plants",
+		},
+	}
+	inst := item.Task{
+		ID:          "inst",
+		Date:        item.NewDate(2024, 10, 7),
+		RecurParent: parent.ID,
+		TaskBody: item.TaskBody{
+			Title: "water plants",
+		},
+	}
+	orphan := item.Task{
+		ID:          "orphan",
+		Date:        item.NewDate(2024, 10, 7),
+		RecurParent: "deleted",
+		TaskBody: item.TaskBody{
+			Title: "water plants",
+		},
+	}
+	for i, tsk := range []item.Task{parent, inst, orphan} {
+		if err := mems.Task(nil).Store(tsk); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		main      []string
+		expOrigin int
+	}{
+		{
+			name: "no origin",
+			main: []string{"1"},
+		},
+		{
+			name:      "origin",
+			main:      []string{"2"},
+			expOrigin: 1,
+		},
+		{
+			name: "deleted origin",
+			main: []string{"3"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := task.NewShowArgs(0).Parse(tc.main, nil)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			res, err := cmd.Do(mems, nil)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			origin := res.(task.ShowResult).Origin
+			var actOrigin int
+			if origin != nil {
+				actOrigin = origin.LocalID
+			}
+			if tc.expOrigin != actOrigin {
+				t.Errorf("exp %v, got %v", tc.expOrigin, actOrigin)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/format"
+	"go-mod.ewintr.nl/planner/plan/storage"
+	"go-mod.ewintr.nl/planner/sync/client"
+)
+
+type SkipArgs struct {
+	LocalID int
+	Date    item.Date
+}
+
+func NewSkipArgs() SkipArgs {
+	return SkipArgs{}
+}
+
+// Parse accepts "<lid> skip [date]" and "skip <lid> [date]". Without a date,
+// the local id must be that of an instance and its date is skipped.
+func (sa SkipArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
+	if len(main) < 2 {
+		return nil, command.ErrWrongCommand
+	}
+	var localIDStr string
+	switch {
+	case main[0] == "skip":
+		localIDStr = main[1]
+	case main[1] == "skip":
+		localIDStr = main[0]
+	default:
+		return nil, command.ErrWrongCommand
+	}
+	localID, err := strconv.Atoi(localIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("not a local id: %v", localIDStr)
+	}
+
+	args := SkipArgs{
+		LocalID: localID,
+	}
+	if len(main) > 2 {
+		args.Date = item.NewDateFromString(strings.Join(main[2:], " "))
+		if args.Date.IsZero() {
+			return nil, fmt.Errorf("%w: could not parse date", command.ErrInvalidArg)
+		}
+	}
+
+	return &Skip{args}, nil
+}
+
+type Skip struct {
+	Args SkipArgs
+}
+
+func (s Skip) Do(repos command.Repositories, _ client.Client) (command.CommandResult, error) {
+	tx, err := repos.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	id, err := repos.LocalID(tx).FindOne(s.Args.LocalID)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return nil, fmt.Errorf("could not find local id")
+	case err != nil:
+		return nil, err
+	}
+	tsk, err := repos.Task(tx).FindOne(id)
+	if err != nil {
+		return nil, fmt.Errorf("could not find task")
+	}
+
+	date := s.Args.Date
+	if date.IsZero() {
+		if tsk.RecurParent == "" {
+			return nil, fmt.Errorf("%w: a date is needed to skip a recurring task", command.ErrInvalidArg)
+		}
+		date = tsk.Date
+	}
+	parent, err := findSeries(repos.Task(tx), tsk)
+	if err != nil {
+		return nil, err
+	}
+	if !parent.Recurrer.RecursOn(date) {
+		return nil, fmt.Errorf("task does not recur on %s", date.String())
+	}
+
+	parent.Recurrer = item.WithException(parent.Recurrer, date)
+	if err := repos.Task(tx).Store(parent); err != nil {
+		return nil, fmt.Errorf("could not store task: %v", err)
+	}
+	it, err := parent.Item()
+	if err != nil {
+		return nil, fmt.Errorf("could not convert task to sync item: %v", err)
+	}
+	if err := repos.Sync(tx).Store(it); err != nil {
+		return nil, fmt.Errorf("could not store sync item: %v", err)
+	}
+
+	// the instance might be spawned already, on the server or here. Its id
+	// is known up front, so it can be deleted either way.
+	inst := it
+	inst.ID = item.InstanceID(parent.ID, date)
+	inst.Date = date
+	inst.Recurrer = nil
+	inst.RecurNext = item.Date{}
+	inst.RecurParent = parent.ID
+	inst.Deleted = true
+	if err := repos.Sync(tx).Store(inst); err != nil {
+		return nil, fmt.Errorf("could not store sync item: %v", err)
+	}
+	if err := deleteLocal(repos, tx, inst.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not skip date: %v", err)
+	}
+
+	return SkipResult{
+		Title: parent.Title,
+		Date:  date,
+	}, nil
+}
+
+type SkipResult struct {
+	Title string
+	Date  item.Date
+}
+
+func (sr SkipResult) Render() string {
+	return fmt.Sprintf("skipped task %s on %s", format.Bold(sr.Title), format.Bold(sr.Date.String()))
+}
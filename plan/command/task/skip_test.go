@@ -0,0 +1,138 @@
+package task_test
+
+import (
+	"testing"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command/task"
+	"go-mod.ewintr.nl/planner/plan/storage/memory"
+)
+
+func TestSkip(t *testing.T) {
+	t.Parallel()
+
+	parent := item.Task{
+		ID:        "parent",
+		Recurrer:  item.NewRecurrer("2024-12-02, weekly, monday"),
+		RecurNext: item.NewDate(2024, 12, 2),
+		TaskBody:  item.TaskBody{Title: "weekly"},
+	}
+	instance := item.Task{
+		ID:          item.InstanceID(parent.ID, item.NewDate(2024, 12, 23)),
+		Date:        item.NewDate(2024, 12, 23),
+		RecurParent: parent.ID,
+		TaskBody:    item.TaskBody{Title: "weekly"},
+	}
+	single := item.Task{
+		ID:       "single",
+		Date:     item.NewDate(2024, 12, 23),
+		TaskBody: item.TaskBody{Title: "single"},
+	}
+
+	for _, tc := range []struct {
+		name          string
+		main          []string
+		expParseErr   bool
+		expDoErr      bool
+		expRecurrer   string
+		expInstanceOK bool
+	}{
+		{
+			name:        "wrong command",
+			main:        []string{"1", "skipping"},
+			expParseErr: true,
+		},
+		{
+			name:        "invalid date",
+			main:        []string{"1", "skip", "someday"},
+			expParseErr: true,
+		},
+		{
+			name:     "no date for recurring task",
+			main:     []string{"1", "skip"},
+			expDoErr: true,
+		},
+		{
+			name:     "not recurring",
+			main:     []string{"3", "skip", "2024-12-23"},
+			expDoErr: true,
+		},
+		{
+			name:     "does not recur on date",
+			main:     []string{"1", "skip", "2024-12-24"},
+			expDoErr: true,
+		},
+		{
+			name:          "future date",
+			main:          []string{"skip", "1", "2024-12-30"},
+			expRecurrer:   "2024-12-02, weekly, monday, except 2024-12-30",
+			expInstanceOK: true,
+		},
+		{
+			name:        "spawned date",
+			main:        []string{"1", "skip", "2024-12-23"},
+			expRecurrer: "2024-12-02, weekly, monday, except 2024-12-23",
+		},
+		{
+			name:        "instance",
+			main:        []string{"2", "skip"},
+			expRecurrer: "2024-12-02, weekly, monday, except 2024-12-23",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			mems := memory.New()
+			for i, tsk := range []item.Task{parent, instance, single} {
+				if err := mems.Task(nil).Store(tsk); err != nil {
+					t.Errorf("exp nil, got %v", err)
+				}
+				if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+					t.Errorf("exp nil, got %v", err)
+				}
+			}
+
+			// parse
+			cmd, actParseErr := task.NewSkipArgs().Parse(tc.main, nil)
+			if tc.expParseErr != (actParseErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expParseErr, actParseErr)
+			}
+			if tc.expParseErr {
+				return
+			}
+
+			// do
+			_, actDoErr := cmd.Do(mems, nil)
+			if tc.expDoErr != (actDoErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expDoErr, actDoErr)
+			}
+			if tc.expDoErr {
+				return
+			}
+
+			// check
+			actParent, err := mems.Task(nil).FindOne(parent.ID)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if actParent.Recurrer.String() != tc.expRecurrer {
+				t.Errorf("exp %v, got %v", tc.expRecurrer, actParent.Recurrer.String())
+			}
+			_, err = mems.Task(nil).FindOne(instance.ID)
+			if tc.expInstanceOK != (err == nil) {
+				t.Errorf("exp %v, got %v", tc.expInstanceOK, err)
+			}
+			updated, err := mems.Sync(nil).FindAll()
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if len(updated) != 2 {
+				t.Errorf("exp 2, got %d", len(updated))
+			}
+			for _, u := range updated {
+				if u.ID != parent.ID && !u.Deleted {
+					t.Errorf("exp deleted instance, got %v", u)
+				}
+			}
+		})
+	}
+}
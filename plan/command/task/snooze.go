@@ -0,0 +1,139 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/cli/arg"
+	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/format"
+	"go-mod.ewintr.nl/planner/sync/client"
+)
+
+type SnoozeArgs struct {
+	fieldTPL map[string][]string
+	LocalID  int
+	Sub      int
+	// Until is the first day the task shows up again, zero to wake it now
+	Until item.Date
+}
+
+func NewSnoozeArgs() SnoozeArgs {
+	return SnoozeArgs{
+		fieldTPL: map[string][]string{
+			"until": {"until"},
+		},
+	}
+}
+
+// Parse reads "<lid> snooze <period>", like 3d or 2w, or the date to snooze
+// until in the until field. A period of "off" wakes the task up again.
+func (sa SnoozeArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
+	if len(main) < 2 {
+		return nil, command.ErrWrongCommand
+	}
+	var localIDStr string
+	switch {
+	case main[0] == "snooze":
+		localIDStr = main[1]
+	case main[1] == "snooze":
+		localIDStr = main[0]
+	default:
+		return nil, command.ErrWrongCommand
+	}
+	localID, sub, err := parseLocalRef(localIDStr)
+	if err != nil {
+		return nil, err
+	}
+	fields, err = arg.ResolveFields(fields, sa.fieldTPL)
+	if err != nil {
+		return nil, err
+	}
+
+	today := item.Today()
+	period := strings.Join(main[2:], " ")
+	val, hasUntil := fields["until"]
+	var until item.Date
+	switch {
+	case period != "" && hasUntil:
+		return nil, fmt.Errorf("%w: give either a period or an until date", command.ErrInvalidArg)
+	case period == "off":
+	case period != "":
+		if until = item.ParseDate("+"+period, today); until.IsZero() {
+			until = item.ParseDate(period, today)
+		}
+		if until.IsZero() {
+			return nil, fmt.Errorf("%w: could not parse period", command.ErrInvalidArg)
+		}
+	case hasUntil:
+		if until = item.ParseDate(val, today); until.IsZero() {
+			return nil, fmt.Errorf("%w: could not parse until date", command.ErrInvalidArg)
+		}
+	default:
+		return nil, fmt.Errorf("%w: snooze needs a period, like 3d, or an until date", command.ErrInvalidArg)
+	}
+	if !until.IsZero() && !until.After(today) {
+		return nil, fmt.Errorf("%w: can only snooze until a day after today", command.ErrInvalidArg)
+	}
+
+	return &Snooze{
+		Args: SnoozeArgs{
+			LocalID: localID,
+			Sub:     sub,
+			Until:   until,
+		},
+	}, nil
+}
+
+// Snooze hides a task from the lists until a later day. The planned date
+// stays the same.
+type Snooze struct {
+	Args SnoozeArgs
+}
+
+func (s Snooze) Do(repos command.Repositories, _ client.Client) (command.CommandResult, error) {
+	tx, err := repos.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	tsk, err := findByLocalRef(repos, tx, s.Args.LocalID, s.Args.Sub)
+	if err != nil {
+		return nil, err
+	}
+	tsk.SnoozeUntil = s.Args.Until
+	if err := repos.Task(tx).Store(tsk); err != nil {
+		return nil, fmt.Errorf("could not store task: %v", err)
+	}
+	it, err := tsk.Item()
+	if err != nil {
+		return nil, fmt.Errorf("could not convert task to sync item: %v", err)
+	}
+	if err := repos.Sync(tx).Store(it); err != nil {
+		return nil, fmt.Errorf("could not store sync item: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not snooze task: %v", err)
+	}
+
+	return SnoozeResult{
+		Title: tsk.Title,
+		Until: tsk.SnoozeUntil,
+	}, nil
+}
+
+type SnoozeResult struct {
+	Title string
+	Until item.Date
+}
+
+func (sr SnoozeResult) Render() string {
+	if sr.Until.IsZero() {
+		return fmt.Sprintf("task %s is no longer snoozed", format.Bold(sr.Title))
+	}
+
+	return fmt.Sprintf("snoozed task %s until %s", format.Bold(sr.Title), format.Bold(sr.Until.String()))
+}
@@ -0,0 +1,159 @@
+package task_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command/task"
+	"go-mod.ewintr.nl/planner/plan/storage/memory"
+)
+
+func TestSnoozeParse(t *testing.T) {
+	t.Parallel()
+
+	today := item.Today()
+	for _, tc := range []struct {
+		name     string
+		main     []string
+		fields   map[string]string
+		expUntil item.Date
+		expErr   bool
+	}{
+		{
+			name:     "days",
+			main:     []string{"1", "snooze", "3d"},
+			expUntil: today.Add(3),
+		},
+		{
+			name:     "weeks",
+			main:     []string{"snooze", "1", "2w"},
+			expUntil: today.Add(14),
+		},
+		{
+			name:     "date",
+			main:     []string{"1", "snooze", "next", "week"},
+			expUntil: item.ParseDate("next week", today),
+		},
+		{
+			name:     "until",
+			main:     []string{"1", "snooze"},
+			fields:   map[string]string{"until": "monday"},
+			expUntil: item.ParseDate("monday", today),
+		},
+		{
+			name: "off",
+			main: []string{"1", "snooze", "off"},
+		},
+		{
+			name:   "nothing",
+			main:   []string{"1", "snooze"},
+			expErr: true,
+		},
+		{
+			name:   "both",
+			main:   []string{"1", "snooze", "3d"},
+			fields: map[string]string{"until": "monday"},
+			expErr: true,
+		},
+		{
+			name:   "past",
+			main:   []string{"1", "snooze", "yesterday"},
+			expErr: true,
+		},
+		{
+			name:   "invalid",
+			main:   []string{"1", "snooze", "a", "while"},
+			expErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, actErr := task.NewSnoozeArgs().Parse(tc.main, tc.fields)
+			if tc.expErr != (actErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expErr, actErr)
+			}
+			if tc.expErr {
+				return
+			}
+			snoozeCmd, ok := cmd.(*task.Snooze)
+			if !ok {
+				t.Errorf("exp true, got false")
+			}
+			if !snoozeCmd.Args.Until.Equal(tc.expUntil) {
+				t.Errorf("exp %v, got %v", tc.expUntil, snoozeCmd.Args.Until)
+			}
+		})
+	}
+}
+
+func TestSnooze(t *testing.T) {
+	t.Parallel()
+
+	today := item.Today()
+	mems := memory.New()
+	for i, tsk := range []item.Task{
+		{ID: "a", Date: today, TaskBody: item.TaskBody{Title: "a"}},
+		{ID: "b", Date: today, TaskBody: item.TaskBody{Title: "b"}},
+	} {
+		if err := mems.Task(nil).Store(tsk); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+	}
+
+	cmd := task.Snooze{Args: task.SnoozeArgs{LocalID: 2, Until: today.Add(2)}}
+	if _, err := cmd.Do(mems, nil); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	actTask, err := mems.Task(nil).FindOne("b")
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if !actTask.Date.Equal(today) {
+		t.Errorf("exp %v, got %v", today, actTask.Date)
+	}
+	updated, err := mems.Sync(nil).FindAll()
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if len(updated) != 1 {
+		t.Errorf("exp 1, got %v", len(updated))
+	}
+
+	for _, tc := range []struct {
+		name string
+		args task.ListArgs
+		exp  []string
+	}{
+		{
+			name: "hidden",
+			exp:  []string{"a"},
+		},
+		{
+			name: "show",
+			args: task.ListArgs{ShowHidden: true},
+			exp:  []string{"a", "b"},
+		},
+		{
+			name: "only",
+			args: task.ListArgs{OnlyHidden: true},
+			exp:  []string{"b"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := task.List{Args: tc.args}.Do(mems, nil)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			act := make([]string, 0)
+			for _, tl := range res.(task.ListResult).Tasks {
+				act = append(act, tl.Task.Title)
+			}
+			if diff := cmp.Diff(tc.exp, act); diff != "" {
+				t.Errorf("(exp -, got +)\n%s", diff)
+			}
+		})
+	}
+}
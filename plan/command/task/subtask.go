@@ -0,0 +1,119 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/storage"
+)
+
+// parseLocalRef reads a local id, or a reference to a subtask as the local
+// id of the parent and the position of the subtask, like 12.3. sub is zero
+// for a plain local id.
+func parseLocalRef(s string) (int, int, error) {
+	lidStr, subStr, isSub := strings.Cut(s, ".")
+	lid, err := strconv.Atoi(lidStr)
+	if err != nil || lid < 1 {
+		return 0, 0, fmt.Errorf("not a local id: %v", s)
+	}
+	if !isSub {
+		return lid, 0, nil
+	}
+	sub, err := strconv.Atoi(subStr)
+	if err != nil || sub < 1 {
+		return 0, 0, fmt.Errorf("not a local id: %v", s)
+	}
+
+	return lid, sub, nil
+}
+
+// formatLocalRef is the reverse of parseLocalRef
+func formatLocalRef(lid, sub int) string {
+	if sub == 0 {
+		return strconv.Itoa(lid)
+	}
+
+	return fmt.Sprintf("%d.%d", lid, sub)
+}
+
+// findByLocalRef returns the task with local id lid, or its subtask at
+// position sub
+func findByLocalRef(repos command.Repositories, tx *storage.Tx, lid, sub int) (item.Task, error) {
+	id, err := repos.LocalID(tx).FindOne(lid)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return item.Task{}, fmt.Errorf("could not find local id")
+	case err != nil:
+		return item.Task{}, err
+	}
+	tsk, err := repos.Task(tx).FindOne(id)
+	if err != nil {
+		return item.Task{}, fmt.Errorf("could not find task")
+	}
+	if sub == 0 {
+		return tsk, nil
+	}
+
+	subtasks, err := findSubtasks(repos.Task(tx), tsk.ID)
+	if err != nil {
+		return item.Task{}, err
+	}
+	for _, st := range subtasks {
+		if st.Position == sub {
+			return st, nil
+		}
+	}
+
+	return item.Task{}, fmt.Errorf("could not find subtask %s", formatLocalRef(lid, sub))
+}
+
+// findSubtasks returns the subtasks of a task, in order of position
+func findSubtasks(repo storage.Task, id string) ([]item.Task, error) {
+	subtasks, err := repo.FindMany(storage.TaskListParams{Parent: id})
+	if err != nil {
+		return nil, fmt.Errorf("could not get subtasks: %v", err)
+	}
+	sort.Slice(subtasks, func(i, j int) bool {
+		return subtasks[i].Position < subtasks[j].Position
+	})
+
+	return subtasks, nil
+}
+
+// subtasksByParent groups the subtasks among tasks by the id of their
+// parent, each group in order of position
+func subtasksByParent(tasks []item.Task) map[string][]item.Task {
+	subtasks := make(map[string][]item.Task)
+	for _, tsk := range tasks {
+		if tsk.Parent != "" {
+			subtasks[tsk.Parent] = append(subtasks[tsk.Parent], tsk)
+		}
+	}
+	for _, sts := range subtasks {
+		sort.Slice(sts, func(i, j int) bool {
+			return sts[i].Position < sts[j].Position
+		})
+	}
+
+	return subtasks
+}
+
+// checkSubtask tells whether tsk can be a subtask of parent. Subtasks can
+// not have subtasks of their own, and recurring tasks can not be part of it.
+func checkSubtask(tsk, parent item.Task) error {
+	switch {
+	case parent.Parent != "":
+		return fmt.Errorf("%w: a subtask can not have subtasks", command.ErrInvalidArg)
+	case parent.Recurrer != nil || parent.RecurParent != "":
+		return fmt.Errorf("%w: a recurring task can not have subtasks", command.ErrInvalidArg)
+	case tsk.Recurrer != nil:
+		return fmt.Errorf("%w: a subtask can not recur", command.ErrInvalidArg)
+	}
+
+	return nil
+}
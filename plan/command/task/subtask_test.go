@@ -0,0 +1,179 @@
+package task_test
+
+import (
+	"testing"
+
+	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command/task"
+	"go-mod.ewintr.nl/planner/plan/storage"
+	"go-mod.ewintr.nl/planner/plan/storage/memory"
+)
+
+func TestSubtasks(t *testing.T) {
+	t.Parallel()
+
+	mems := memory.New()
+
+	t.Log("add parent and subtasks")
+	for _, tc := range []struct {
+		main   []string
+		fields map[string]string
+	}{
+		{main: []string{"add", "paint", "room"}},
+		{main: []string{"add", "buy", "paint"}, fields: map[string]string{"parent": "1"}},
+		{main: []string{"add", "tape", "windows"}, fields: map[string]string{"parent": "1"}},
+	} {
+		cmd, err := task.NewAddArgs().Parse(tc.main, tc.fields)
+		if err != nil {
+			t.Fatalf("exp nil, got %v", err)
+		}
+		if _, err := cmd.Do(mems, nil); err != nil {
+			t.Fatalf("exp nil, got %v", err)
+		}
+	}
+	parentID, err := mems.LocalID(nil).FindOne(1)
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	subtasks, err := mems.Task(nil).FindMany(storage.TaskListParams{Parent: parentID})
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	positions := make(map[string]int)
+	for _, st := range subtasks {
+		positions[st.Title] = st.Position
+	}
+	if positions["buy paint"] != 1 || positions["tape windows"] != 2 {
+		t.Errorf("exp positions 1 and 2, got %v", positions)
+	}
+
+	t.Log("no nested subtasks")
+	cmd, err := task.NewAddArgs().Parse([]string{"add", "go", "to", "shop"}, map[string]string{"parent": "2"})
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	if _, err := cmd.Do(mems, nil); err == nil {
+		t.Errorf("exp error, got nil")
+	}
+
+	t.Log("show subtask")
+	cmd, err = task.NewShowArgs(0).Parse([]string{"1.2"}, nil)
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	res, err := cmd.Do(mems, nil)
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	showRes := res.(task.ShowResult)
+	if showRes.Task.Title != "tape windows" {
+		t.Errorf("exp tape windows, got %v", showRes.Task.Title)
+	}
+
+	t.Log("unknown subtask")
+	cmd, err = task.NewShowArgs(0).Parse([]string{"1.5"}, nil)
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	if _, err := cmd.Do(mems, nil); err == nil {
+		t.Errorf("exp error, got nil")
+	}
+
+	t.Log("update subtask")
+	cmd, err = task.NewUpdateArgs().Parse([]string{"1.1", "update", "buy", "white", "paint"}, nil)
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	if _, err := cmd.Do(mems, nil); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	cmd, err = task.NewUpdateArgs().Parse([]string{"1.1", "update"}, map[string]string{"rec": "daily"})
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	if _, err := cmd.Do(mems, nil); err == nil {
+		t.Errorf("exp error, got nil")
+	}
+
+	t.Log("done requires subtasks to be done")
+	cmd, err = task.NewDeleteArgs().Parse([]string{"1", "done"}, map[string]string{})
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	if _, err := cmd.Do(mems, nil); err == nil {
+		t.Errorf("exp error, got nil")
+	}
+
+	t.Log("done subtask")
+	cmd, err = task.NewDeleteArgs().Parse([]string{"1.1", "done"}, map[string]string{})
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	if _, err := cmd.Do(mems, nil); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	t.Log("scope does not cascade")
+	cmd, err = task.NewDeleteArgs().Parse([]string{"1", "done"}, map[string]string{"scope": "all"})
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	if _, err := cmd.Do(mems, nil); err == nil {
+		t.Errorf("exp error, got nil")
+	}
+
+	t.Log("done cascades")
+	cmd, err = task.NewDeleteArgs().Parse([]string{"1", "done"}, map[string]string{"cascade": "yes"})
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	res, err = cmd.Do(mems, nil)
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	if delRes := res.(task.DeleteResult); delRes.Subtasks != 1 {
+		t.Errorf("exp 1, got %v", delRes.Subtasks)
+	}
+	left, err := mems.Task(nil).FindMany(storage.TaskListParams{})
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if len(left) != 0 {
+		t.Errorf("exp 0, got %v", len(left))
+	}
+
+	t.Log("synced")
+	items, err := mems.Sync(nil).FindAll()
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	for _, it := range items {
+		if !it.Deleted {
+			t.Errorf("exp deleted, got %v", it)
+		}
+	}
+}
+
+func TestSubtaskRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	exp := item.Task{
+		ID: "id",
+		TaskBody: item.TaskBody{
+			Title:    "step",
+			Parent:   "parent-id",
+			Position: 3,
+		},
+	}
+	it, err := exp.Item()
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	act, err := item.NewTask(it)
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	if diff := item.TaskDiff(exp, act); diff != "" {
+		t.Errorf("(exp -, got +)\n%s", diff)
+	}
+}
@@ -0,0 +1,87 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-mod.ewintr.nl/planner/plan/command"
+	"go-mod.ewintr.nl/planner/plan/format"
+	"go-mod.ewintr.nl/planner/sync/client"
+)
+
+type TagsArgs struct{}
+
+func NewTagsArgs() TagsArgs {
+	return TagsArgs{}
+}
+
+func (ta TagsArgs) Parse(main []string, fields map[string]string) (command.Command, error) {
+	if len(main) != 1 || main[0] != "tags" {
+		return nil, command.ErrWrongCommand
+	}
+
+	return Tags{}, nil
+}
+
+type Tags struct{}
+
+func (ts Tags) Do(repos command.Repositories, _ client.Client) (command.CommandResult, error) {
+	tx, err := repos.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	tags, err := repos.Task(tx).Tags()
+	if err != nil {
+		return nil, fmt.Errorf("could not find tags: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not list tags: %v", err)
+	}
+
+	return TagsResult{
+		Tags: tags,
+	}, nil
+}
+
+type TagsResult struct {
+	Tags map[string]int
+}
+
+func (tsr TagsResult) Render() string {
+	if len(tsr.Tags) == 0 {
+		return "\nno tags to display\n"
+	}
+
+	tags := make([]string, 0, len(tsr.Tags))
+	for tag := range tsr.Tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	data := [][]string{{"tags", "count"}}
+	for _, t := range tags {
+		data = append(data, []string{fmt.Sprintf("@%s", t), fmt.Sprintf("%d", tsr.Tags[t])})
+	}
+
+	return fmt.Sprintf("\n%s\n", format.Table(data))
+}
+
+// formatTags shows tags the way they are typed in
+func formatTags(tags []string) string {
+	res := make([]string, 0, len(tags))
+	for _, t := range tags {
+		res = append(res, fmt.Sprintf("@%s", t))
+	}
+
+	return strings.Join(res, " ")
+}
+
+// splitTags splits a list of tags that is separated by commas or spaces
+func splitTags(val string) []string {
+	return strings.FieldsFunc(val, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
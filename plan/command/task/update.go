@@ -1,10 +1,8 @@
 package task
 
 import (
-	"errors"
 	"fmt"
 	"slices"
-	"strconv"
 	"strings"
 	"time"
 
@@ -20,12 +18,19 @@ type UpdateArgs struct {
 	fieldTPL   map[string][]string
 	NeedUpdate []string
 	LocalID    int
+	Sub        int
 	Title      string
 	Project    string
 	Date       item.Date
+	Deadline   item.Date
 	Time       item.Time
 	Duration   time.Duration
 	Recurrer   item.Recurrer
+	Priority   item.Priority
+	Tags       []string
+	Notes      string
+	BlockedBy  []string
+	Scope      Scope
 }
 
 func NewUpdateArgs() UpdateArgs {
@@ -33,9 +38,15 @@ func NewUpdateArgs() UpdateArgs {
 		fieldTPL: map[string][]string{
 			"project":  {"p", "project"},
 			"date":     {"d", "date", "on"},
+			"deadline": {"due", "deadline"},
 			"time":     {"t", "time", "at"},
 			"duration": {"dur", "duration", "for"},
 			"recurrer": {"rec", "recurrer"},
+			"priority": {"prio", "priority"},
+			"tags":     {"tag", "tags"},
+			"notes":    {"notes"},
+			"blocked":  {"after", "blockedby"},
+			"scope":    {"scope"},
 		},
 	}
 }
@@ -54,9 +65,9 @@ func (ua UpdateArgs) Parse(main []string, fields map[string]string) (command.Com
 	default:
 		return nil, command.ErrWrongCommand
 	}
-	localID, err := strconv.Atoi(localIDStr)
+	localID, sub, err := parseLocalRef(localIDStr)
 	if err != nil {
-		return nil, fmt.Errorf("not a local id: %v", main[1])
+		return nil, err
 	}
 	fields, err = arg.ResolveFields(fields, ua.fieldTPL)
 	if err != nil {
@@ -65,7 +76,9 @@ func (ua UpdateArgs) Parse(main []string, fields map[string]string) (command.Com
 	args := UpdateArgs{
 		NeedUpdate: make([]string, 0),
 		LocalID:    localID,
+		Sub:        sub,
 		Title:      strings.Join(main[2:], " "),
+		Scope:      ScopeThis,
 	}
 
 	if val, ok := fields["project"]; ok {
@@ -82,6 +95,16 @@ func (ua UpdateArgs) Parse(main []string, fields map[string]string) (command.Com
 			args.Date = d
 		}
 	}
+	if val, ok := fields["deadline"]; ok {
+		args.NeedUpdate = append(args.NeedUpdate, "deadline")
+		if val != "" {
+			d := item.NewDateFromString(val)
+			if d.IsZero() {
+				return nil, fmt.Errorf("%w: could not parse deadline", command.ErrInvalidArg)
+			}
+			args.Deadline = d
+		}
+	}
 	if val, ok := fields["time"]; ok {
 		args.NeedUpdate = append(args.NeedUpdate, "time")
 		if val != "" {
@@ -105,19 +128,54 @@ func (ua UpdateArgs) Parse(main []string, fields map[string]string) (command.Com
 	if val, ok := fields["recurrer"]; ok {
 		args.NeedUpdate = append(args.NeedUpdate, "recurrer")
 		if val != "" {
-			rec := item.NewRecurrer(val)
+			rec := item.ParseRecurrer(val, item.Today())
 			if rec == nil {
 				return nil, fmt.Errorf("%w: could not parse recurrer", command.ErrInvalidArg)
 			}
 			args.Recurrer = rec
 		}
 	}
+	if val, ok := fields["priority"]; ok {
+		args.NeedUpdate = append(args.NeedUpdate, "priority")
+		prio, err := item.ParsePriority(val)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", command.ErrInvalidArg, err)
+		}
+		args.Priority = prio
+	}
+	if val, ok := fields["tags"]; ok {
+		args.NeedUpdate = append(args.NeedUpdate, "tags")
+		args.Tags = item.NewTags(splitTags(val)...)
+	}
+	if val, ok := fields["blocked"]; ok {
+		args.NeedUpdate = append(args.NeedUpdate, "blocked")
+		if args.BlockedBy, err = parseLocalRefs(val); err != nil {
+			return nil, err
+		}
+	}
+	if val, ok := fields["notes"]; ok {
+		args.NeedUpdate = append(args.NeedUpdate, "notes")
+		args.Notes = val
+	}
+
+	if val, ok := fields["scope"]; ok {
+		scope, err := ParseScope(val)
+		if err != nil {
+			return nil, err
+		}
+		args.Scope = scope
+	}
+	if args.Scope != ScopeThis && slices.Contains(args.NeedUpdate, "date") {
+		return nil, fmt.Errorf("%w: date can only be changed for a single instance", command.ErrInvalidArg)
+	}
 
-	return &Update{args}, nil
+	return &Update{args: args}, nil
 }
 
 type Update struct {
 	args UpdateArgs
+	// blockerIDs are the ids of the tasks in args.BlockedBy
+	blockerIDs []string
 }
 
 func (u Update) Do(repos command.Repositories, _ client.Client) (command.CommandResult, error) {
@@ -127,21 +185,133 @@ func (u Update) Do(repos command.Repositories, _ client.Client) (command.Command
 	}
 	defer tx.Rollback()
 
-	id, err := repos.LocalID(tx).FindOne(u.args.LocalID)
-	switch {
-	case errors.Is(err, storage.ErrNotFound):
-		return nil, fmt.Errorf("could not find local id")
-	case err != nil:
+	tsk, err := findByLocalRef(repos, tx, u.args.LocalID, u.args.Sub)
+	if err != nil {
 		return nil, err
 	}
+	oldTitle := tsk.Title
+	if slices.Contains(u.args.NeedUpdate, "blocked") {
+		if u.blockerIDs, err = resolveBlockers(repos, tx, u.args.BlockedBy); err != nil {
+			return nil, err
+		}
+		if err := checkCycle(repos.Task(tx), tsk.ID, u.blockerIDs); err != nil {
+			return nil, err
+		}
+	}
 
-	tsk, err := repos.Task(tx).FindOne(id)
+	var changes map[string]string
+	var instances int
+	if u.args.Scope == ScopeThis {
+		changes, err = u.updateTask(repos, tx, tsk)
+	} else {
+		changes, instances, err = u.updateSeries(repos, tx, tsk)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("could not find task")
+		return nil, err
 	}
-	changes := make(map[string]string)
-	oldTitle := tsk.Title
 
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not update task: %v", err)
+	}
+
+	return UpdateResult{
+		Title:     oldTitle,
+		Changes:   changes,
+		Instances: instances,
+	}, nil
+}
+
+func (u Update) updateTask(repos command.Repositories, tx *storage.Tx, tsk item.Task) (map[string]string, error) {
+	if slices.Contains(u.args.NeedUpdate, "recurrer") && u.args.Recurrer != nil {
+		if tsk.Parent != "" {
+			return nil, fmt.Errorf("%w: a subtask can not recur", command.ErrInvalidArg)
+		}
+		subtasks, err := findSubtasks(repos.Task(tx), tsk.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(subtasks) > 0 {
+			return nil, fmt.Errorf("%w: a task with subtasks can not recur", command.ErrInvalidArg)
+		}
+	}
+	changes := u.apply(&tsk, true)
+	if !tsk.Valid() {
+		return nil, fmt.Errorf("task is unvalid")
+	}
+	if err := repos.Task(tx).Store(tsk); err != nil {
+		return nil, fmt.Errorf("could not store task: %v", err)
+	}
+
+	it, err := tsk.Item()
+	if err != nil {
+		return nil, fmt.Errorf("could not convert task to sync item: %v", err)
+	}
+	if err := repos.Sync(tx).Store(it); err != nil {
+		return nil, fmt.Errorf("could not store sync item: %v", err)
+	}
+
+	return changes, nil
+}
+
+// updateSeries changes the recurring task and the local instances that fall
+// within the scope. Only the recurring task is sent to the server, marked to
+// have the server rewrite the instances it already spawned, including those
+// this client does not know about yet.
+func (u Update) updateSeries(repos command.Repositories, tx *storage.Tx, tsk item.Task) (map[string]string, int, error) {
+	parent, err := findSeries(repos.Task(tx), tsk)
+	if err != nil {
+		return nil, 0, err
+	}
+	from := seriesFrom(u.args.Scope, tsk, parent)
+
+	changes := u.apply(&parent, true)
+	if !parent.Valid() {
+		return nil, 0, fmt.Errorf("task is unvalid")
+	}
+	if u.args.Scope == ScopeAll && parent.Recurrer != nil && from.After(parent.Recurrer.First()) {
+		from = parent.Recurrer.First()
+	}
+	if err := repos.Task(tx).Store(parent); err != nil {
+		return nil, 0, fmt.Errorf("could not store task: %v", err)
+	}
+	it, err := parent.Item()
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not convert task to sync item: %v", err)
+	}
+	it.RewriteFrom = from
+	if err := repos.Sync(tx).Store(it); err != nil {
+		return nil, 0, fmt.Errorf("could not store sync item: %v", err)
+	}
+
+	instances, err := repos.Task(tx).FindMany(storage.TaskListParams{RecurParent: parent.ID})
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not get instances: %v", err)
+	}
+	var count int
+	for _, inst := range instances {
+		if from.After(inst.Date) {
+			continue
+		}
+		count++
+		if parent.Recurrer == nil || !parent.Recurrer.RecursOn(inst.Date) {
+			if err := deleteLocal(repos, tx, inst.ID); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+		u.apply(&inst, false)
+		if err := repos.Task(tx).Store(inst); err != nil {
+			return nil, 0, fmt.Errorf("could not store task: %v", err)
+		}
+	}
+
+	return changes, count, nil
+}
+
+// apply makes the requested changes to tsk and reports them. Instances of a
+// series do not get a recurrer of their own, so that is optional.
+func (u Update) apply(tsk *item.Task, withRecurrer bool) map[string]string {
+	changes := make(map[string]string)
 	if u.args.Title != "" {
 		tsk.Title = u.args.Title
 		changes["title"] = u.args.Title
@@ -154,6 +324,10 @@ func (u Update) Do(repos command.Repositories, _ client.Client) (command.Command
 		tsk.Date = u.args.Date
 		changes["date"] = tsk.Date.String()
 	}
+	if slices.Contains(u.args.NeedUpdate, "deadline") {
+		tsk.Deadline = u.args.Deadline
+		changes["deadline"] = tsk.Deadline.String()
+	}
 	if slices.Contains(u.args.NeedUpdate, "time") {
 		tsk.Time = u.args.Time
 		changes["time"] = tsk.Time.String()
@@ -162,41 +336,39 @@ func (u Update) Do(repos command.Repositories, _ client.Client) (command.Command
 		tsk.Duration = u.args.Duration
 		changes["duration"] = tsk.Duration.String()
 	}
-	if slices.Contains(u.args.NeedUpdate, "recurrer") {
-		tsk.Recurrer = u.args.Recurrer
-		tsk.RecurNext = tsk.Recurrer.First()
-		changes["recurrer"] = tsk.Recurrer.String()
+	if slices.Contains(u.args.NeedUpdate, "priority") {
+		tsk.Priority = u.args.Priority
+		changes["priority"] = tsk.Priority.String()
 	}
-
-	if !tsk.Valid() {
-		return nil, fmt.Errorf("task is unvalid")
+	if slices.Contains(u.args.NeedUpdate, "tags") {
+		tsk.Tags = u.args.Tags
+		changes["tags"] = formatTags(tsk.Tags)
 	}
-
-	if err := repos.Task(tx).Store(tsk); err != nil {
-		return nil, fmt.Errorf("could not store task: %v", err)
+	if slices.Contains(u.args.NeedUpdate, "blocked") {
+		tsk.BlockedBy = u.blockerIDs
+		changes["blocked by"] = strings.Join(u.args.BlockedBy, ", ")
 	}
-
-	it, err := tsk.Item()
-	if err != nil {
-		return nil, fmt.Errorf("could not convert task to sync item: %v", err)
+	if slices.Contains(u.args.NeedUpdate, "notes") {
+		tsk.Notes = u.args.Notes
+		changes["notes"] = summarizeNotes(tsk.Notes)
 	}
-	if err := repos.Sync(tx).Store(it); err != nil {
-		return nil, fmt.Errorf("could not store sync item: %v", err)
-	}
-
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("could not update task: %v", err)
+	if withRecurrer && slices.Contains(u.args.NeedUpdate, "recurrer") {
+		tsk.Recurrer = u.args.Recurrer
+		tsk.RecurNext = item.Date{}
+		changes["recurrer"] = ""
+		if tsk.Recurrer != nil {
+			tsk.RecurNext = tsk.Recurrer.First()
+			changes["recurrer"] = tsk.Recurrer.String()
+		}
 	}
 
-	return UpdateResult{
-		Title:   oldTitle,
-		Changes: changes,
-	}, nil
+	return changes
 }
 
 type UpdateResult struct {
-	Title   string
-	Changes map[string]string
+	Title     string
+	Changes   map[string]string
+	Instances int
 }
 
 func (ur UpdateResult) Render() string {
@@ -204,5 +376,10 @@ func (ur UpdateResult) Render() string {
 	for k, v := range ur.Changes {
 		chStr = append(chStr, fmt.Sprintf("%s to %s", format.Bold(k), format.Bold(v)))
 	}
-	return fmt.Sprintf("updated task %s, set %s", format.Bold(ur.Title), strings.Join(chStr, ", "))
+	msg := fmt.Sprintf("updated task %s, set %s", format.Bold(ur.Title), strings.Join(chStr, ", "))
+	if ur.Instances > 0 {
+		msg += fmt.Sprintf(", and %d instances", ur.Instances)
+	}
+
+	return msg
 }
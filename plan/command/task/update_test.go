@@ -5,9 +5,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/command"
 	"go-mod.ewintr.nl/planner/plan/command/task"
+	"go-mod.ewintr.nl/planner/plan/storage"
 	"go-mod.ewintr.nl/planner/plan/storage/memory"
+	"go-mod.ewintr.nl/planner/sync/client"
 )
 
 func TestUpdateExecute(t *testing.T) {
@@ -160,6 +164,53 @@ func TestUpdateExecute(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "invalid priority",
+			localID: lid,
+			main:    []string{"update", fmt.Sprintf("%d", lid)},
+			fields: map[string]string{
+				"prio": "p9",
+			},
+			expParseErr: true,
+		},
+		{
+			name:    "priority",
+			localID: lid,
+			main:    []string{"update", fmt.Sprintf("%d", lid)},
+			fields: map[string]string{
+				"prio": "p3",
+			},
+			expTask: item.Task{
+				ID:   tskID,
+				Date: item.NewDate(2024, 10, 6),
+				TaskBody: item.TaskBody{
+					Title:    title,
+					Project:  project,
+					Time:     aTime,
+					Duration: oneHour,
+					Priority: item.Priority3,
+				},
+			},
+		},
+		{
+			name:    "tags",
+			localID: lid,
+			main:    []string{"update", fmt.Sprintf("%d", lid)},
+			fields: map[string]string{
+				"tags": "@home,phone",
+			},
+			expTask: item.Task{
+				ID:   tskID,
+				Date: item.NewDate(2024, 10, 6),
+				TaskBody: item.TaskBody{
+					Title:    title,
+					Project:  project,
+					Time:     aTime,
+					Duration: oneHour,
+					Tags:     []string{"home", "phone"},
+				},
+			},
+		},
 		{
 			name: "invalid recurrer",
 			main: []string{"update", fmt.Sprintf("%d", lid)},
@@ -242,3 +293,189 @@ func TestUpdateExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateSeries(t *testing.T) {
+	t.Parallel()
+
+	parent := item.Task{
+		ID:        "parent",
+		Recurrer:  item.NewRecurrer("2024-10-01, daily"),
+		RecurNext: item.NewDate(2024, 10, 8),
+		TaskBody:  item.TaskBody{Title: "old"},
+	}
+	instances := []item.Task{
+		{ID: "a", Date: item.NewDate(2024, 10, 5), RecurParent: parent.ID, TaskBody: item.TaskBody{Title: "old"}},
+		{ID: "b", Date: item.NewDate(2024, 10, 6), RecurParent: parent.ID, TaskBody: item.TaskBody{Title: "old"}},
+		{ID: "c", Date: item.NewDate(2024, 10, 7), RecurParent: parent.ID, TaskBody: item.TaskBody{Title: "old"}},
+	}
+	single := item.Task{ID: "single", Date: item.NewDate(2024, 10, 6), TaskBody: item.TaskBody{Title: "old"}}
+
+	for _, tc := range []struct {
+		name           string
+		main           []string
+		fields         map[string]string
+		expParseErr    bool
+		expDoErr       bool
+		expTitles      map[string]string
+		expRewriteFrom item.Date
+	}{
+		{
+			name:        "invalid scope",
+			main:        []string{"3", "update", "new"},
+			fields:      map[string]string{"scope": "some"},
+			expParseErr: true,
+		},
+		{
+			name:        "date of series",
+			main:        []string{"3", "update"},
+			fields:      map[string]string{"scope": "future", "date": "2024-10-10"},
+			expParseErr: true,
+		},
+		{
+			name:     "not recurring",
+			main:     []string{"5", "update", "new"},
+			fields:   map[string]string{"scope": "all"},
+			expDoErr: true,
+		},
+		{
+			name:   "this instance",
+			main:   []string{"3", "update", "new"},
+			fields: map[string]string{"scope": "this"},
+			expTitles: map[string]string{
+				"parent": "old", "a": "old", "b": "new", "c": "old",
+			},
+		},
+		{
+			name:   "this and future",
+			main:   []string{"3", "update", "new"},
+			fields: map[string]string{"scope": "future"},
+			expTitles: map[string]string{
+				"parent": "new", "a": "old", "b": "new", "c": "new",
+			},
+			expRewriteFrom: item.NewDate(2024, 10, 6),
+		},
+		{
+			name:   "entire series",
+			main:   []string{"3", "update", "new"},
+			fields: map[string]string{"scope": "all", "rec": "2024-10-01, every 2 days"},
+			expTitles: map[string]string{
+				"parent": "new", "a": "new", "c": "new",
+			},
+			expRewriteFrom: item.NewDate(2024, 10, 1),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			mems := memory.New()
+			for i, tsk := range append([]item.Task{parent}, append(instances, single)...) {
+				if err := mems.Task(nil).Store(tsk); err != nil {
+					t.Errorf("exp nil, got %v", err)
+				}
+				if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+					t.Errorf("exp nil, got %v", err)
+				}
+			}
+
+			// parse
+			cmd, actErr := task.NewUpdateArgs().Parse(tc.main, tc.fields)
+			if tc.expParseErr != (actErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expParseErr, actErr)
+			}
+			if tc.expParseErr {
+				return
+			}
+
+			// do
+			_, actDoErr := cmd.Do(mems, nil)
+			if tc.expDoErr != (actDoErr != nil) {
+				t.Errorf("exp %v, got %v", tc.expDoErr, actDoErr)
+			}
+			if tc.expDoErr {
+				return
+			}
+
+			// check
+			actTasks, err := mems.Task(nil).FindMany(storage.TaskListParams{})
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			actTitles := make(map[string]string)
+			for _, tsk := range actTasks {
+				if tsk.ID != single.ID {
+					actTitles[tsk.ID] = tsk.Title
+				}
+			}
+			if diff := cmp.Diff(tc.expTitles, actTitles); diff != "" {
+				t.Errorf("(exp +, got -)\n%s", diff)
+			}
+			updated, err := mems.Sync(nil).FindAll()
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if len(updated) != 1 {
+				t.Errorf("exp 1, got %v", len(updated))
+				return
+			}
+			if !tc.expRewriteFrom.Equal(updated[0].RewriteFrom) {
+				t.Errorf("exp %v, got %v", tc.expRewriteFrom, updated[0].RewriteFrom)
+			}
+		})
+	}
+}
+
+func TestUpdateSeriesTwice(t *testing.T) {
+	t.Parallel()
+
+	mems := memory.New()
+	syncClient := client.NewMemory()
+	parent := item.Task{
+		ID:        "parent",
+		Recurrer:  item.NewRecurrer("2024-10-01, daily"),
+		RecurNext: item.NewDate(2024, 10, 8),
+		TaskBody:  item.TaskBody{Title: "old"},
+	}
+	inst := item.Task{ID: "a", Date: item.NewDate(2024, 10, 5), RecurParent: parent.ID, TaskBody: item.TaskBody{Title: "old"}}
+	for i, tsk := range []item.Task{parent, inst} {
+		if err := mems.Task(nil).Store(tsk); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		if err := mems.LocalID(nil).Store(tsk.ID, i+1); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+	}
+
+	// the second update does not rewrite, but must not undo the first
+	for _, upd := range []struct {
+		main   []string
+		fields map[string]string
+	}{
+		{main: []string{"2", "update", "new"}, fields: map[string]string{"scope": "all"}},
+		{main: []string{"1", "update"}, fields: map[string]string{"project": "home"}},
+	} {
+		cmd, err := task.NewUpdateArgs().Parse(upd.main, upd.fields)
+		if err != nil {
+			t.Fatalf("exp nil, got %v", err)
+		}
+		if _, err := cmd.Do(mems, syncClient); err != nil {
+			t.Fatalf("exp nil, got %v", err)
+		}
+	}
+	cmd, err := command.NewSyncArgs().Parse([]string{"sync"}, nil)
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	if _, err := cmd.Do(mems, syncClient); err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+
+	sent, err := syncClient.Updated([]item.Kind{item.KindTask}, time.Time{})
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("exp 1, got %v", len(sent))
+	}
+	if exp := item.NewDate(2024, 10, 1); !sent[0].RewriteFrom.Equal(exp) {
+		t.Errorf("exp %v, got %v", exp, sent[0].RewriteFrom)
+	}
+}
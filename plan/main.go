@@ -1,10 +1,13 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"go-mod.ewintr.nl/planner/item"
 	"go-mod.ewintr.nl/planner/plan/cli"
 	"go-mod.ewintr.nl/planner/plan/storage/sqlite"
 	"go-mod.ewintr.nl/planner/sync/client"
@@ -27,6 +30,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	if conf.Timezone != "" {
+		loc, err := time.LoadLocation(conf.Timezone)
+		if err != nil {
+			fmt.Printf("could not load timezone: %s\n", err)
+			os.Exit(1)
+		}
+		item.SetTimezone(loc)
+	}
+
+	dayStart, dayEnd, err := conf.WorkingHours()
+	if err != nil {
+		fmt.Printf("could not set working hours: %s\n", err)
+		os.Exit(1)
+	}
+
 	repos, err := sqlite.NewSqlites(conf.DBPath)
 	if err != nil {
 		fmt.Printf("could not open db file: %s\n", err)
@@ -35,7 +53,12 @@ func main() {
 
 	syncClient := client.New(conf.SyncURL, conf.ApiKey)
 
-	cli := cli.NewCLI(repos, syncClient)
+	cli := cli.NewCLI(repos, syncClient, cli.Options{
+		AutoRollover:   conf.AutoRollover,
+		DeadlineWindow: conf.DeadlineWindow,
+		DayStart:       dayStart,
+		DayEnd:         dayEnd,
+	})
 	if err := cli.Run(os.Args[1:]); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -43,9 +66,31 @@ func main() {
 }
 
 type Configuration struct {
-	DBPath  string `yaml:"db_path"`
-	SyncURL string `yaml:"sync_url"`
-	ApiKey  string `yaml:"api_key"`
+	DBPath         string `yaml:"db_path"`
+	SyncURL        string `yaml:"sync_url"`
+	ApiKey         string `yaml:"api_key"`
+	Timezone       string `yaml:"timezone"`
+	DeadlineWindow int    `yaml:"deadline_window"`
+	AutoRollover   bool   `yaml:"auto_rollover"`
+	DayStart       string `yaml:"day_start"`
+	DayEnd         string `yaml:"day_end"`
+}
+
+// WorkingHours reads day_start and day_end. They are set together, or not at
+// all, in which case both are zero.
+func (c Configuration) WorkingHours() (item.Time, item.Time, error) {
+	if c.DayStart == "" && c.DayEnd == "" {
+		return item.Time{}, item.Time{}, nil
+	}
+	start, end := item.NewTimeFromString(c.DayStart), item.NewTimeFromString(c.DayEnd)
+	if start.IsZero() || end.IsZero() {
+		return item.Time{}, item.Time{}, errors.New("day_start and day_end need to be set together, as times like 09:00")
+	}
+	if !end.Time().After(start.Time()) {
+		return item.Time{}, item.Time{}, errors.New("day_end needs to be after day_start")
+	}
+
+	return start, end, nil
 }
 
 func LoadConfig(path string) (Configuration, error) {
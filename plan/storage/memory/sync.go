@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"go-mod.ewintr.nl/planner/item"
+	"go-mod.ewintr.nl/planner/plan/storage"
 )
 
 type Sync struct {
@@ -38,6 +39,9 @@ func (r *Sync) Store(e item.Item) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	if queued, ok := r.items[e.ID]; ok {
+		e.RewriteFrom = storage.EarliestRewrite(queued.RewriteFrom, e.RewriteFrom)
+	}
 	r.items[e.ID] = e
 
 	return nil
@@ -79,3 +79,17 @@ func (t *Task) Projects() (map[string]int, error) {
 
 	return projects, nil
 }
+
+func (t *Task) Tags() (map[string]int, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	tags := make(map[string]int)
+	for _, tsk := range t.tasks {
+		for _, tag := range tsk.Tags {
+			tags[tag]++
+		}
+	}
+
+	return tags, nil
+}
@@ -3,6 +3,7 @@ package memory
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"go-mod.ewintr.nl/planner/item"
 	"go-mod.ewintr.nl/planner/plan/storage"
 )
@@ -25,6 +26,9 @@ func TestTask(t *testing.T) {
 	tsk1 := item.Task{
 		ID:   "id-1",
 		Date: item.NewDate(2024, 12, 29),
+		TaskBody: item.TaskBody{
+			Tags: []string{"home"},
+		},
 	}
 	if err := mem.Store(tsk1); err != nil {
 		t.Errorf("exp nil, got %v", err)
@@ -32,6 +36,9 @@ func TestTask(t *testing.T) {
 
 	tsk2 := item.Task{
 		ID: "id-2",
+		TaskBody: item.TaskBody{
+			Tags: []string{"home", "phone"},
+		},
 	}
 	if err := mem.Store(tsk2); err != nil {
 		t.Errorf("exp nil, got %v", err)
@@ -66,4 +73,24 @@ func TestTask(t *testing.T) {
 		t.Errorf("(exp -, got +)\n%s", diff)
 	}
 
+	t.Log("find tagged")
+	actTasks, actErr = mem.FindMany(storage.TaskListParams{
+		Tags:    []string{"home"},
+		NotTags: []string{"phone"},
+	})
+	if actErr != nil {
+		t.Errorf("exp nil, got %v", actErr)
+	}
+	if diff := item.TaskDiffs([]item.Task{tsk1}, actTasks); diff != "" {
+		t.Errorf("(exp -, got +)\n%s", diff)
+	}
+
+	t.Log("tags")
+	actTags, actErr := mem.Tags()
+	if actErr != nil {
+		t.Errorf("exp nil, got %v", actErr)
+	}
+	if diff := cmp.Diff(map[string]int{"home": 2, "phone": 1}, actTags); diff != "" {
+		t.Errorf("(exp +, got -)\n%s", diff)
+	}
 }
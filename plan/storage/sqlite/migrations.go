@@ -51,4 +51,21 @@ var migrations = []string{
 	  "date" TEXT NOT NULL DEFAULT '',
 	  "recur" TEXT NOT NULL DEFAULT '',
 	  "recur_next" TEXT NOT NULL DEFAULT '')`,
+	`ALTER TABLE items ADD COLUMN recur_parent TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tasks ADD COLUMN recur_parent TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE items ADD COLUMN rewrite_from TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE tasks ADD COLUMN created TEXT NOT NULL DEFAULT ''`,
+	`CREATE TABLE task_tags (
+	  "task_id" TEXT NOT NULL,
+	  "tag" TEXT NOT NULL,
+	  PRIMARY KEY (task_id, tag))`,
+	`ALTER TABLE tasks ADD COLUMN notes TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tasks ADD COLUMN parent TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tasks ADD COLUMN position INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE tasks ADD COLUMN blocked_by TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tasks ADD COLUMN deadline TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tasks ADD COLUMN postponed INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE tasks ADD COLUMN snooze_until TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tasks ADD COLUMN rolled_over TEXT NOT NULL DEFAULT ''`,
 }
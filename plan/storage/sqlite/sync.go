@@ -2,6 +2,7 @@ package sqlite
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,7 +15,7 @@ type Sync struct {
 }
 
 func (s *Sync) FindAll() ([]item.Item, error) {
-	rows, err := s.tx.Query("SELECT id, kind, updated, deleted, date, recurrer, recur_next, body FROM items")
+	rows, err := s.tx.Query("SELECT id, kind, updated, deleted, date, recurrer, recur_next, recur_parent, rewrite_from, body FROM items")
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to query items: %v", ErrSqliteFailure, err)
 	}
@@ -23,8 +24,8 @@ func (s *Sync) FindAll() ([]item.Item, error) {
 	var items []item.Item
 	for rows.Next() {
 		var i item.Item
-		var updatedStr, dateStr, recurStr, recurNextStr string
-		err := rows.Scan(&i.ID, &i.Kind, &updatedStr, &i.Deleted, &dateStr, &recurStr, &recurNextStr, &i.Body)
+		var updatedStr, dateStr, recurStr, recurNextStr, rewriteFromStr string
+		err := rows.Scan(&i.ID, &i.Kind, &updatedStr, &i.Deleted, &dateStr, &recurStr, &recurNextStr, &i.RecurParent, &rewriteFromStr, &i.Body)
 		if err != nil {
 			return nil, fmt.Errorf("%w: failed to scan item: %v", ErrSqliteFailure, err)
 		}
@@ -35,6 +36,7 @@ func (s *Sync) FindAll() ([]item.Item, error) {
 		i.Date = item.NewDateFromString(dateStr)
 		i.Recurrer = item.NewRecurrer(recurStr)
 		i.RecurNext = item.NewDateFromString(recurNextStr)
+		i.RewriteFrom = item.NewDateFromString(rewriteFromStr)
 
 		items = append(items, i)
 	}
@@ -54,10 +56,19 @@ func (s *Sync) Store(i item.Item) error {
 	if i.Recurrer != nil {
 		recurStr = i.Recurrer.String()
 	}
+	var queuedStr string
+	err := s.tx.QueryRow(`SELECT rewrite_from FROM items WHERE id = ?`, i.ID).Scan(&queuedStr)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return fmt.Errorf("%w: failed to get queued item: %v", ErrSqliteFailure, err)
+	default:
+		i.RewriteFrom = storage.EarliestRewrite(item.NewDateFromString(queuedStr), i.RewriteFrom)
+	}
 
-	_, err := s.tx.Exec(
-		`INSERT OR REPLACE INTO items (id, kind, updated, deleted, date, recurrer, recur_next, body)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+	_, err = s.tx.Exec(
+		`INSERT OR REPLACE INTO items (id, kind, updated, deleted, date, recurrer, recur_next, recur_parent, rewrite_from, body)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		i.ID,
 		i.Kind,
 		i.Updated.UTC().Format(time.RFC3339),
@@ -65,6 +76,8 @@ func (s *Sync) Store(i item.Item) error {
 		i.Date.String(),
 		recurStr,
 		i.RecurNext.String(),
+		i.RecurParent,
+		i.RewriteFrom.String(),
 		sql.NullString{String: i.Body, Valid: i.Body != ""}, // This allows empty string but not NULL
 	)
 	if err != nil {
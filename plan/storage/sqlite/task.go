@@ -2,7 +2,9 @@ package sqlite
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"go-mod.ewintr.nl/planner/item"
@@ -18,11 +20,12 @@ func (t *SqliteTask) Store(tsk item.Task) error {
 	if tsk.Recurrer != nil {
 		recurStr = tsk.Recurrer.String()
 	}
+	blockedByStr := strings.Join(tsk.BlockedBy, ",")
 	if _, err := t.tx.Exec(`
 INSERT INTO tasks
-(id, title, project, date, time, duration, recurrer)
+(id, title, project, date, time, duration, recurrer, recur_parent, priority, created, notes, parent, position, blocked_by, deadline, postponed, snooze_until, rolled_over)
 VALUES
-(?, ?, ?, ?, ?, ?, ?)
+(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 ON CONFLICT(id) DO UPDATE
 SET
 title=?,
@@ -30,25 +33,44 @@ project=?,
 date=?,
 time=?,
 duration=?,
-recurrer=?
+recurrer=?,
+recur_parent=?,
+priority=?,
+created=?,
+notes=?,
+parent=?,
+position=?,
+blocked_by=?,
+deadline=?,
+postponed=?,
+snooze_until=?,
+rolled_over=?
 `,
-		tsk.ID, tsk.Title, tsk.Project, tsk.Date.String(), tsk.Time.String(), tsk.Duration.String(), recurStr,
-		tsk.Title, tsk.Project, tsk.Date.String(), tsk.Time.String(), tsk.Duration.String(), recurStr); err != nil {
+		tsk.ID, tsk.Title, tsk.Project, tsk.Date.String(), tsk.Time.String(), tsk.Duration.String(), recurStr, tsk.RecurParent, tsk.Priority, tsk.Created.String(), tsk.Notes, tsk.Parent, tsk.Position, blockedByStr, tsk.Deadline.String(), tsk.Postponed, tsk.SnoozeUntil.String(), tsk.RolledOver.String(),
+		tsk.Title, tsk.Project, tsk.Date.String(), tsk.Time.String(), tsk.Duration.String(), recurStr, tsk.RecurParent, tsk.Priority, tsk.Created.String(), tsk.Notes, tsk.Parent, tsk.Position, blockedByStr, tsk.Deadline.String(), tsk.Postponed, tsk.SnoozeUntil.String(), tsk.RolledOver.String()); err != nil {
 		return fmt.Errorf("%w: %v", ErrSqliteFailure, err)
 	}
+	if _, err := t.tx.Exec(`DELETE FROM task_tags WHERE task_id = ?`, tsk.ID); err != nil {
+		return fmt.Errorf("%w: %v", ErrSqliteFailure, err)
+	}
+	for _, tag := range tsk.Tags {
+		if _, err := t.tx.Exec(`INSERT INTO task_tags (task_id, tag) VALUES (?, ?)`, tsk.ID, tag); err != nil {
+			return fmt.Errorf("%w: %v", ErrSqliteFailure, err)
+		}
+	}
 	return nil
 }
 
 func (t *SqliteTask) FindOne(id string) (item.Task, error) {
 	var tsk item.Task
-	var dateStr, timeStr, recurStr, durStr string
+	var dateStr, timeStr, recurStr, durStr, createdStr, blockedByStr, deadlineStr, snoozeStr, rolledOverStr string
 	err := t.tx.QueryRow(`
-SELECT id, title, project, date, time, duration, recurrer
+SELECT id, title, project, date, time, duration, recurrer, recur_parent, priority, created, notes, parent, position, blocked_by, deadline, postponed, snooze_until, rolled_over
 FROM tasks
-WHERE id = ?`, id).Scan(&tsk.ID, &tsk.Title, &tsk.Project, &dateStr, &timeStr, &durStr, &recurStr)
+WHERE id = ?`, id).Scan(&tsk.ID, &tsk.Title, &tsk.Project, &dateStr, &timeStr, &durStr, &recurStr, &tsk.RecurParent, &tsk.Priority, &createdStr, &tsk.Notes, &tsk.Parent, &tsk.Position, &blockedByStr, &deadlineStr, &tsk.Postponed, &snoozeStr, &rolledOverStr)
 	switch {
-	case err == sql.ErrNoRows:
-		return item.Task{}, fmt.Errorf("event not found: %w", err)
+	case errors.Is(err, sql.ErrNoRows):
+		return item.Task{}, storage.ErrNotFound
 	case err != nil:
 		return item.Task{}, fmt.Errorf("%w: %v", ErrSqliteFailure, err)
 	}
@@ -60,12 +82,22 @@ WHERE id = ?`, id).Scan(&tsk.ID, &tsk.Title, &tsk.Project, &dateStr, &timeStr, &
 	}
 	tsk.Duration = dur
 	tsk.Recurrer = item.NewRecurrer(recurStr)
+	tsk.Created = item.NewDateFromString(createdStr)
+	tsk.BlockedBy = splitIDs(blockedByStr)
+	tsk.Deadline = item.NewDateFromString(deadlineStr)
+	tsk.SnoozeUntil = item.NewDateFromString(snoozeStr)
+	tsk.RolledOver = item.NewDateFromString(rolledOverStr)
+	tags, err := t.findTags(id)
+	if err != nil {
+		return item.Task{}, err
+	}
+	tsk.Tags = tags[id]
 
 	return tsk, nil
 }
 
 func (t *SqliteTask) FindMany(params storage.TaskListParams) ([]item.Task, error) {
-	query := `SELECT id, title, project, date, time, duration, recurrer FROM tasks`
+	query := `SELECT id, title, project, date, time, duration, recurrer, recur_parent, priority, created, notes, parent, position, blocked_by, deadline, postponed, snooze_until, rolled_over FROM tasks`
 	args := []interface{}{}
 
 	where := make([]string, 0)
@@ -87,6 +119,26 @@ func (t *SqliteTask) FindMany(params storage.TaskListParams) ([]item.Task, error
 		where = append(where, `project = ?`)
 		args = append(args, params.Project)
 	}
+	if params.RecurParent != "" {
+		where = append(where, `recur_parent = ?`)
+		args = append(args, params.RecurParent)
+	}
+	if params.Parent != "" {
+		where = append(where, `parent = ?`)
+		args = append(args, params.Parent)
+	}
+	if params.Priority != item.PriorityNone {
+		where = append(where, `priority = ?`)
+		args = append(args, params.Priority)
+	}
+	for _, tag := range params.Tags {
+		where = append(where, `id IN (SELECT task_id FROM task_tags WHERE tag = ?)`)
+		args = append(args, tag)
+	}
+	for _, tag := range params.NotTags {
+		where = append(where, `id NOT IN (SELECT task_id FROM task_tags WHERE tag = ?)`)
+		args = append(args, tag)
+	}
 	if dateNonEmpty {
 		where = append(where, `date != ""`)
 	}
@@ -106,8 +158,8 @@ func (t *SqliteTask) FindMany(params storage.TaskListParams) ([]item.Task, error
 	defer rows.Close()
 	for rows.Next() {
 		var tsk item.Task
-		var dateStr, timeStr, recurStr, durStr string
-		if err := rows.Scan(&tsk.ID, &tsk.Title, &tsk.Project, &dateStr, &timeStr, &durStr, &recurStr); err != nil {
+		var dateStr, timeStr, recurStr, durStr, createdStr, blockedByStr, deadlineStr, snoozeStr, rolledOverStr string
+		if err := rows.Scan(&tsk.ID, &tsk.Title, &tsk.Project, &dateStr, &timeStr, &durStr, &recurStr, &tsk.RecurParent, &tsk.Priority, &createdStr, &tsk.Notes, &tsk.Parent, &tsk.Position, &blockedByStr, &deadlineStr, &tsk.Postponed, &snoozeStr, &rolledOverStr); err != nil {
 			return nil, fmt.Errorf("%w: %v", ErrSqliteFailure, err)
 		}
 		dur, err := time.ParseDuration(durStr)
@@ -118,13 +170,55 @@ func (t *SqliteTask) FindMany(params storage.TaskListParams) ([]item.Task, error
 		tsk.Time = item.NewTimeFromString(timeStr)
 		tsk.Duration = dur
 		tsk.Recurrer = item.NewRecurrer(recurStr)
+		tsk.Created = item.NewDateFromString(createdStr)
+		tsk.BlockedBy = splitIDs(blockedByStr)
+		tsk.Deadline = item.NewDateFromString(deadlineStr)
+		tsk.SnoozeUntil = item.NewDateFromString(snoozeStr)
+		tsk.RolledOver = item.NewDateFromString(rolledOverStr)
 
 		tasks = append(tasks, tsk)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSqliteFailure, err)
+	}
+
+	tags, err := t.findTags("")
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i].Tags = tags[tasks[i].ID]
+	}
 
 	return tasks, nil
 }
 
+// findTags returns the tags per task id, for all tasks if id is empty
+func (t *SqliteTask) findTags(id string) (map[string][]string, error) {
+	query := `SELECT task_id, tag FROM task_tags`
+	args := []interface{}{}
+	if id != "" {
+		query += ` WHERE task_id = ?`
+		args = append(args, id)
+	}
+	rows, err := t.tx.Query(query+` ORDER BY tag`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSqliteFailure, err)
+	}
+	defer rows.Close()
+
+	tags := make(map[string][]string)
+	for rows.Next() {
+		var taskID, tag string
+		if err := rows.Scan(&taskID, &tag); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSqliteFailure, err)
+		}
+		tags[taskID] = append(tags[taskID], tag)
+	}
+
+	return tags, nil
+}
+
 func (t *SqliteTask) Delete(id string) error {
 	result, err := t.tx.Exec(`
 DELETE FROM tasks
@@ -132,6 +226,9 @@ WHERE id = ?`, id)
 	if err != nil {
 		return fmt.Errorf("%w: %v", ErrSqliteFailure, err)
 	}
+	if _, err := t.tx.Exec(`DELETE FROM task_tags WHERE task_id = ?`, id); err != nil {
+		return fmt.Errorf("%w: %v", ErrSqliteFailure, err)
+	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
@@ -164,3 +261,32 @@ func (t *SqliteTask) Projects() (map[string]int, error) {
 
 	return result, nil
 }
+
+func (t *SqliteTask) Tags() (map[string]int, error) {
+	rows, err := t.tx.Query(`SELECT tag, count(*) FROM task_tags GROUP BY tag`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSqliteFailure, err)
+	}
+
+	result := make(map[string]int)
+	defer rows.Close()
+	for rows.Next() {
+		var tag string
+		var count int
+		if err := rows.Scan(&tag, &count); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSqliteFailure, err)
+		}
+		result[tag] = count
+	}
+
+	return result, nil
+}
+
+// splitIDs reads a list of ids as it is stored, a nil slice if it is empty
+func splitIDs(ids string) []string {
+	if ids == "" {
+		return nil
+	}
+
+	return strings.Split(ids, ",")
+}
@@ -27,6 +27,8 @@ type LocalID interface {
 
 type Sync interface {
 	FindAll() ([]item.Item, error)
+	// Store replaces an item that was not sent yet, but keeps the earliest
+	// RewriteFrom of the two. See EarliestRewrite.
 	Store(i item.Item) error
 	DeleteAll() error
 	SetLastUpdate(ts time.Time) error
@@ -39,6 +41,12 @@ type TaskListParams struct {
 	From        item.Date
 	To          item.Date
 	Project     string
+	RecurParent string
+	Parent      string
+	Priority    item.Priority
+	// Tags must all be on the task, NotTags none of them
+	Tags    []string
+	NotTags []string
 }
 
 type Task interface {
@@ -47,6 +55,7 @@ type Task interface {
 	FindMany(params TaskListParams) ([]item.Task, error)
 	Delete(id string) error
 	Projects() (map[string]int, error)
+	Tags() (map[string]int, error)
 }
 
 type Schedule interface {
@@ -55,6 +64,18 @@ type Schedule interface {
 	Delete(id string) error
 }
 
+// EarliestRewrite returns the date from which the instances of a recurring
+// item must be rewritten, when an item that still waits to be sent with
+// RewriteFrom queued is replaced by one with next. A later change must not
+// undo the rewrite of an earlier one, so it is the earliest date that is set.
+func EarliestRewrite(queued, next item.Date) item.Date {
+	if queued.IsZero() || (!next.IsZero() && queued.After(next)) {
+		return next
+	}
+
+	return queued
+}
+
 func MatchTask(tsk item.Task, params TaskListParams) bool {
 	if params.HasRecurrer && tsk.Recurrer == nil {
 		return false
@@ -71,6 +92,25 @@ func MatchTask(tsk item.Task, params TaskListParams) bool {
 	if params.Project != "" && params.Project != tsk.Project {
 		return false
 	}
+	if params.RecurParent != "" && params.RecurParent != tsk.RecurParent {
+		return false
+	}
+	if params.Parent != "" && params.Parent != tsk.Parent {
+		return false
+	}
+	if params.Priority != item.PriorityNone && params.Priority != tsk.Priority {
+		return false
+	}
+	for _, tag := range params.Tags {
+		if !tsk.HasTag(tag) {
+			return false
+		}
+	}
+	for _, tag := range params.NotTags {
+		if tsk.HasTag(tag) {
+			return false
+		}
+	}
 
 	return true
 }
@@ -11,12 +11,14 @@ func TestMatch(t *testing.T) {
 	t.Parallel()
 
 	tskMatch := item.Task{
-		ID:       "id",
-		Date:     item.NewDate(2024, 12, 29),
-		Recurrer: item.NewRecurrer("2024-12-29, daily"),
+		ID:          "id",
+		Date:        item.NewDate(2024, 12, 29),
+		Recurrer:    item.NewRecurrer("2024-12-29, daily"),
+		RecurParent: "parent",
 		TaskBody: item.TaskBody{
-			Title:   "name",
-			Project: "p1",
+			Title:    "name",
+			Project:  "p1",
+			Priority: item.Priority1,
 		},
 	}
 	tskNotMatch := item.Task{
@@ -57,6 +59,18 @@ func TestMatch(t *testing.T) {
 				Project: "p1",
 			},
 		},
+		{
+			name: "recur parent",
+			params: storage.TaskListParams{
+				RecurParent: "parent",
+			},
+		},
+		{
+			name: "priority",
+			params: storage.TaskListParams{
+				Priority: item.Priority1,
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			if !storage.MatchTask(tskMatch, tc.params) {
@@ -69,6 +83,30 @@ func TestMatch(t *testing.T) {
 	}
 }
 
+func TestEarliestRewrite(t *testing.T) {
+	t.Parallel()
+
+	early, late := item.NewDate(2024, 10, 1), item.NewDate(2024, 10, 6)
+	for _, tc := range []struct {
+		name   string
+		queued item.Date
+		next   item.Date
+		exp    item.Date
+	}{
+		{name: "none"},
+		{name: "new", next: late, exp: late},
+		{name: "kept", queued: early, exp: early},
+		{name: "earlier", queued: late, next: early, exp: early},
+		{name: "later", queued: early, next: late, exp: early},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if act := storage.EarliestRewrite(tc.queued, tc.next); !act.Equal(tc.exp) {
+				t.Errorf("exp %v, got %v", tc.exp, act)
+			}
+		})
+	}
+}
+
 func TestNextLocalId(t *testing.T) {
 	t.Parallel()
 
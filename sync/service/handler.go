@@ -15,24 +15,53 @@ import (
 )
 
 type Server struct {
-	syncer Syncer
-	apiKey string
-	logger *slog.Logger
+	syncer  Syncer
+	recur   *Recur
+	apiKey  string
+	health  *Health
+	metrics *Metrics
+	logger  *slog.Logger
 }
 
-func NewServer(syncer Syncer, apiKey string, logger *slog.Logger) *Server {
+func NewServer(syncer Syncer, recur *Recur, apiKey string, health *Health, metrics *Metrics, logger *slog.Logger) *Server {
 	return &Server{
-		syncer: syncer,
-		apiKey: apiKey,
-		logger: logger,
+		syncer:  syncer,
+		recur:   recur,
+		apiKey:  apiKey,
+		health:  health,
+		metrics: metrics,
+		logger:  logger,
 	}
 }
 
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+	s.serve(sw, r)
+	s.metrics.Request(routeName(r.URL.Path), sw.status, time.Since(start))
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/metrics" {
+		s.metrics.Handler().ServeHTTP(w, r)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if r.URL.Path == "/" {
+	switch r.URL.Path {
+	case "/":
 		Index(w, r)
 		return
+	case "/healthz":
+		writeHealthReport(w, s.health.Live())
+		return
+	case "/readyz":
+		report := s.health.Ready()
+		if report.Status != statusOK {
+			s.logger.Error("service not ready", "report", report)
+		}
+		writeHealthReport(w, report)
+		return
 	}
 
 	if r.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", s.apiKey) {
@@ -50,6 +79,8 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		s.SyncGet(w, r)
 	case head == "sync" && r.Method == http.MethodPost:
 		s.SyncPost(w, r)
+	case head == "admin" && tail == "/recur" && r.Method == http.MethodPost:
+		s.AdminRecur(w, r)
 	default:
 		msg := "not found"
 		http.Error(w, fmtError(msg), http.StatusNotFound)
@@ -88,6 +119,7 @@ func (s *Server) SyncGet(w http.ResponseWriter, r *http.Request) {
 		msg := err.Error()
 		http.Error(w, fmtError(msg), http.StatusInternalServerError)
 		s.logger.Error(msg)
+		s.metrics.DBError("updated")
 		return
 	}
 
@@ -100,6 +132,7 @@ func (s *Server) SyncGet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	fmt.Fprint(w, string(body))
+	s.metrics.ItemsServed(items)
 	s.logger.Info("served sync get", "count", len(items), "remoteAddr", getClientIP(r))
 }
 
@@ -146,18 +179,43 @@ func (s *Server) SyncPost(w http.ResponseWriter, r *http.Request) {
 			s.logger.Info(msg)
 			return
 		}
+		if !it.RewriteFrom.IsZero() {
+			if err := s.recur.Rewrite(it); err != nil {
+				msg := err.Error()
+				http.Error(w, fmtError(msg), http.StatusInternalServerError)
+				s.logger.Error(msg)
+				return
+			}
+			s.metrics.ItemWritten(it.Kind)
+			continue
+		}
 		if err := s.syncer.Update(it, time.Now()); err != nil {
 			msg := err.Error()
 			http.Error(w, fmtError(msg), http.StatusInternalServerError)
 			s.logger.Error(msg)
+			s.metrics.DBError("update")
 			return
 		}
+		s.metrics.ItemWritten(it.Kind)
 	}
 	w.WriteHeader(http.StatusNoContent)
 
 	s.logger.Info("served sync post", "count", len(items), "remoteAddr", getClientIP(r))
 }
 
+func (s *Server) AdminRecur(w http.ResponseWriter, r *http.Request) {
+	until, err := s.recur.RunNow()
+	if err != nil {
+		msg := err.Error()
+		http.Error(w, fmtError(msg), http.StatusInternalServerError)
+		s.logger.Error(msg)
+		return
+	}
+
+	fmt.Fprintf(w, `{"status":"ok","until":%q}`, until.String())
+	s.logger.Info("served admin recur", "until", until.String(), "remoteAddr", getClientIP(r))
+}
+
 // ShiftPath splits off the first component of p, which will be cleaned of
 // relative components before processing. head will never contain a slash and
 // tail will always be a rooted path without trailing slash.
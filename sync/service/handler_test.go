@@ -22,7 +22,11 @@ func TestServerServeHTTP(t *testing.T) {
 	t.Parallel()
 
 	apiKey := "test"
-	srv := NewServer(NewMemory(), apiKey, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	mem := NewMemory()
+	metrics := NewMetrics()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	rec := NewRecur(mem, mem, 8, time.UTC, metrics, logger)
+	srv := NewServer(mem, rec, apiKey, NewHealth(mem, rec, time.Hour), metrics, logger)
 
 	for _, tc := range []struct {
 		name      string
@@ -37,6 +41,30 @@ func TestServerServeHTTP(t *testing.T) {
 			method:    http.MethodGet,
 			expStatus: http.StatusOK,
 		},
+		{
+			name:      "metrics always visible",
+			url:       "/metrics",
+			method:    http.MethodGet,
+			expStatus: http.StatusOK,
+		},
+		{
+			name:      "healthz always visible",
+			url:       "/healthz",
+			method:    http.MethodGet,
+			expStatus: http.StatusOK,
+		},
+		{
+			name:      "readyz always visible",
+			url:       "/readyz",
+			method:    http.MethodGet,
+			expStatus: http.StatusOK,
+		},
+		{
+			name:      "sync needs key",
+			url:       "/sync",
+			method:    http.MethodGet,
+			expStatus: http.StatusUnauthorized,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			req, err := http.NewRequest(tc.method, tc.url, nil)
@@ -71,7 +99,10 @@ func TestSyncGet(t *testing.T) {
 	}
 
 	apiKey := "test"
-	srv := NewServer(mem, apiKey, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	metrics := NewMetrics()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	rec := NewRecur(mem, mem, 8, time.UTC, metrics, logger)
+	srv := NewServer(mem, rec, apiKey, NewHealth(mem, rec, time.Hour), metrics, logger)
 
 	for _, tc := range []struct {
 		name      string
@@ -187,10 +218,23 @@ func TestSyncPost(t *testing.T) {
 				{ID: "id-2", Kind: item.KindTask, Updated: time.Date(2024, 9, 6, 12, 0, 0, 0, time.UTC)},
 			},
 		},
+		{
+			name: "rewrite",
+			reqBody: []byte(`[
+  {"id":"id-1","kind":"task","updated":"2024-09-06T08:00:00Z","deleted":true,"recurrer":"2024-09-01, daily","rewriteFrom":"2024-09-06","body":"item"}
+]`),
+			expStatus: http.StatusNoContent,
+			expItems: []item.Item{
+				{ID: "id-1", Kind: item.KindTask, Updated: time.Date(2024, 9, 6, 8, 0, 0, 0, time.UTC)},
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			mem := NewMemory()
-			srv := NewServer(mem, apiKey, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+			metrics := NewMetrics()
+			logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+			rec := NewRecur(mem, mem, 8, time.UTC, metrics, logger)
+			srv := NewServer(mem, rec, apiKey, NewHealth(mem, rec, time.Hour), metrics, logger)
 			req, err := http.NewRequest(http.MethodPost, "/sync", bytes.NewBuffer(tc.reqBody))
 			if err != nil {
 				t.Errorf("exp nil, got %v", err)
@@ -221,3 +265,47 @@ func TestSyncPost(t *testing.T) {
 		})
 	}
 }
+
+func TestAdminRecur(t *testing.T) {
+	t.Parallel()
+
+	apiKey := "test"
+	mem := NewMemory()
+	metrics := NewMetrics()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	rec := NewRecur(mem, mem, 8, time.UTC, metrics, logger)
+	srv := NewServer(mem, rec, apiKey, NewHealth(mem, rec, time.Hour), metrics, logger)
+
+	recurring := item.Item{
+		ID:        "id-1",
+		Kind:      item.KindTask,
+		Recurrer:  item.NewRecurrer("2024-01-01, daily"),
+		RecurNext: item.NewDate(2024, 1, 1),
+		Body:      "body",
+	}
+	if err := mem.Update(recurring, time.Now()); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "/admin/recur", nil)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+	if res.Result().StatusCode != http.StatusOK {
+		t.Errorf("exp %v, got %v", http.StatusOK, res.Result().StatusCode)
+	}
+
+	recurItems, err := mem.ShouldRecur(rec.Today())
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if len(recurItems) != 0 {
+		t.Errorf("exp 0, got %d", len(recurItems))
+	}
+	if _, ok := rec.LastSuccess(); !ok {
+		t.Errorf("exp true, got false")
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	statusOK   = "ok"
+	statusFail = "fail"
+)
+
+type Check struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Detail any    `json:"detail,omitempty"`
+}
+
+type HealthReport struct {
+	Status string           `json:"status"`
+	Checks map[string]Check `json:"checks,omitempty"`
+}
+
+// Health reports on the state of the service and the things it depends on
+type Health struct {
+	checker     Checker
+	recur       *Recur
+	maxRecurAge time.Duration
+}
+
+func NewHealth(checker Checker, recur *Recur, maxRecurAge time.Duration) *Health {
+	return &Health{
+		checker:     checker,
+		recur:       recur,
+		maxRecurAge: maxRecurAge,
+	}
+}
+
+// Live only tells that the process is running and able to serve requests
+func (h *Health) Live() HealthReport {
+	return HealthReport{Status: statusOK}
+}
+
+// Ready checks whether the service can do its work
+func (h *Health) Ready() HealthReport {
+	checks := map[string]Check{
+		"database":   h.checkDatabase(),
+		"migrations": h.checkMigrations(),
+		"recurrence": h.checkRecurrence(),
+	}
+	status := statusOK
+	for _, c := range checks {
+		if c.Status != statusOK {
+			status = statusFail
+		}
+	}
+
+	return HealthReport{
+		Status: status,
+		Checks: checks,
+	}
+}
+
+func (h *Health) checkDatabase() Check {
+	if err := h.checker.Ping(); err != nil {
+		return Check{Status: statusFail, Error: err.Error()}
+	}
+
+	return Check{Status: statusOK}
+}
+
+func (h *Health) checkMigrations() Check {
+	current, expected, err := h.checker.MigrationVersion()
+	if err != nil {
+		return Check{Status: statusFail, Error: err.Error()}
+	}
+	detail := map[string]int{
+		"current":  current,
+		"expected": expected,
+	}
+	if current != expected {
+		return Check{
+			Status: statusFail,
+			Error:  fmt.Sprintf("at migration %d, expected %d", current, expected),
+			Detail: detail,
+		}
+	}
+
+	return Check{Status: statusOK, Detail: detail}
+}
+
+func (h *Health) checkRecurrence() Check {
+	// until the first run finished, measure from the moment the worker was
+	// created, so a fresh service is not reported as wedged right away
+	last, ok := h.recur.LastSuccess()
+	since := last
+	if !ok {
+		since = h.recur.Created()
+	}
+	age := time.Since(since)
+	detail := map[string]string{
+		"age":    age.Truncate(time.Second).String(),
+		"maxAge": h.maxRecurAge.String(),
+	}
+	if ok {
+		detail["lastSuccess"] = last.Format(time.RFC3339)
+	}
+	if age > h.maxRecurAge {
+		return Check{
+			Status: statusFail,
+			Error:  "no successful recurrence run within max age",
+			Detail: detail,
+		}
+	}
+
+	return Check{Status: statusOK, Detail: detail}
+}
+
+func writeHealthReport(w http.ResponseWriter, report HealthReport) {
+	body, err := json.Marshal(report)
+	if err != nil {
+		http.Error(w, fmtError(err.Error()), http.StatusInternalServerError)
+		return
+	}
+	if report.Status != statusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	fmt.Fprint(w, string(body))
+}
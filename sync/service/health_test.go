@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-mod.ewintr.nl/planner/item"
+)
+
+type testChecker struct {
+	pingErr  error
+	current  int
+	expected int
+}
+
+func (tc testChecker) Ping() error { return tc.pingErr }
+
+func (tc testChecker) MigrationVersion() (int, int, error) { return tc.current, tc.expected, nil }
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mem := NewMemory()
+
+	for _, tc := range []struct {
+		name      string
+		checker   testChecker
+		recurRun  bool
+		maxAge    time.Duration
+		expStatus int
+		expChecks map[string]string
+	}{
+		{
+			name:      "all fine",
+			checker:   testChecker{current: 8, expected: 8},
+			maxAge:    time.Hour,
+			expStatus: http.StatusOK,
+			expChecks: map[string]string{
+				"database":   statusOK,
+				"migrations": statusOK,
+				"recurrence": statusOK,
+			},
+		},
+		{
+			name:      "database down",
+			checker:   testChecker{pingErr: errors.New("connection refused"), current: 8, expected: 8},
+			maxAge:    time.Hour,
+			expStatus: http.StatusServiceUnavailable,
+			expChecks: map[string]string{
+				"database":   statusFail,
+				"migrations": statusOK,
+				"recurrence": statusOK,
+			},
+		},
+		{
+			name:      "migrations behind",
+			checker:   testChecker{current: 7, expected: 8},
+			maxAge:    time.Hour,
+			expStatus: http.StatusServiceUnavailable,
+			expChecks: map[string]string{
+				"database":   statusOK,
+				"migrations": statusFail,
+				"recurrence": statusOK,
+			},
+		},
+		{
+			name:      "recurrence never ran",
+			checker:   testChecker{},
+			expStatus: http.StatusServiceUnavailable,
+			expChecks: map[string]string{
+				"database":   statusOK,
+				"migrations": statusOK,
+				"recurrence": statusFail,
+			},
+		},
+		{
+			name:      "recurrence ran",
+			checker:   testChecker{},
+			recurRun:  true,
+			maxAge:    time.Minute,
+			expStatus: http.StatusOK,
+			expChecks: map[string]string{
+				"database":   statusOK,
+				"migrations": statusOK,
+				"recurrence": statusOK,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			metrics := NewMetrics()
+			rec := NewRecur(mem, mem, 8, time.UTC, metrics, logger)
+			if tc.recurRun {
+				if err := rec.Recur(item.NewDate(2024, 1, 1)); err != nil {
+					t.Errorf("exp nil, got %v", err)
+				}
+			}
+			srv := NewServer(mem, rec, "test", NewHealth(tc.checker, rec, tc.maxAge), metrics, logger)
+
+			req, err := http.NewRequest(http.MethodGet, "/readyz", nil)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+			if res.Result().StatusCode != tc.expStatus {
+				t.Errorf("exp %v, got %v", tc.expStatus, res.Result().StatusCode)
+			}
+
+			var report HealthReport
+			if err := json.NewDecoder(res.Result().Body).Decode(&report); err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			for name, exp := range tc.expChecks {
+				if act := report.Checks[name].Status; act != exp {
+					t.Errorf("%s: exp %v, got %v", name, exp, act)
+				}
+			}
+		})
+	}
+}
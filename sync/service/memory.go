@@ -29,6 +29,18 @@ func (m *Memory) Update(item item.Item, ts time.Time) error {
 	return nil
 }
 
+func (m *Memory) UpdateMany(items []item.Item, ts time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, i := range items {
+		i.Updated = ts
+		m.items[i.ID] = i
+	}
+
+	return nil
+}
+
 func (m *Memory) Updated(kinds []item.Kind, timestamp time.Time) ([]item.Item, error) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
@@ -47,9 +59,12 @@ func (m *Memory) Updated(kinds []item.Kind, timestamp time.Time) ([]item.Item, e
 }
 
 func (m *Memory) ShouldRecur(date item.Date) ([]item.Item, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
 	res := make([]item.Item, 0)
 	for _, i := range m.items {
-		if i.Recurrer == nil {
+		if i.Recurrer == nil || i.Deleted {
 			continue
 		}
 		if date.Equal(i.RecurNext) || date.After(i.RecurNext) {
@@ -58,3 +73,24 @@ func (m *Memory) ShouldRecur(date item.Date) ([]item.Item, error) {
 	}
 	return res, nil
 }
+
+func (m *Memory) Instances(parentID string) ([]item.Item, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	res := make([]item.Item, 0)
+	for _, i := range m.items {
+		if i.RecurParent == parentID {
+			res = append(res, i)
+		}
+	}
+	return res, nil
+}
+
+func (m *Memory) Ping() error {
+	return nil
+}
+
+func (m *Memory) MigrationVersion() (int, int, error) {
+	return 0, 0, nil
+}
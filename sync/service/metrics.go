@@ -0,0 +1,159 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go-mod.ewintr.nl/planner/item"
+)
+
+const (
+	metricsNamespace = "plannersync"
+)
+
+// Metrics collects the service metrics in its own registry, so that multiple
+// instances can live side by side in tests
+type Metrics struct {
+	registry         *prometheus.Registry
+	requests         *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	itemsWritten     *prometheus.CounterVec
+	itemsServed      *prometheus.CounterVec
+	recurRuns        prometheus.Counter
+	recurFailures    prometheus.Counter
+	recurSpawned     *prometheus.CounterVec
+	recurLastSuccess prometheus.Gauge
+	dbErrors         *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_requests_total",
+			Help:      "Number of handled http requests.",
+		}, []string{"route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of handled http requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "status"}),
+		itemsWritten: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "items_written_total",
+			Help:      "Number of items received from clients and stored.",
+		}, []string{"kind"}),
+		itemsServed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "items_served_total",
+			Help:      "Number of items sent to clients.",
+		}, []string{"kind"}),
+		recurRuns: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "recur_runs_total",
+			Help:      "Number of started recurrence runs.",
+		}),
+		recurFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "recur_failures_total",
+			Help:      "Number of recurrence runs that ended in an error.",
+		}),
+		recurSpawned: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "recur_spawned_total",
+			Help:      "Number of instances spawned from recurring items.",
+		}, []string{"kind"}),
+		recurLastSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "recur_last_success_timestamp_seconds",
+			Help:      "Unix timestamp of the last successful recurrence run.",
+		}),
+		dbErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "db_errors_total",
+			Help:      "Number of errors returned by the storage backend.",
+		}, []string{"operation"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests, m.requestDuration,
+		m.itemsWritten, m.itemsServed,
+		m.recurRuns, m.recurFailures, m.recurSpawned, m.recurLastSuccess,
+		m.dbErrors,
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) Request(route string, status int, duration time.Duration) {
+	statusStr := strconv.Itoa(status)
+	m.requests.WithLabelValues(route, statusStr).Inc()
+	m.requestDuration.WithLabelValues(route, statusStr).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ItemWritten(kind item.Kind) {
+	m.itemsWritten.WithLabelValues(string(kind)).Inc()
+}
+
+func (m *Metrics) ItemsServed(items []item.Item) {
+	for _, i := range items {
+		m.itemsServed.WithLabelValues(string(i.Kind)).Inc()
+	}
+}
+
+func (m *Metrics) RecurStarted() {
+	m.recurRuns.Inc()
+}
+
+func (m *Metrics) RecurFailed() {
+	m.recurFailures.Inc()
+}
+
+func (m *Metrics) RecurSucceeded(ts time.Time) {
+	m.recurLastSuccess.Set(float64(ts.Unix()))
+}
+
+func (m *Metrics) Spawned(kind item.Kind) {
+	m.recurSpawned.WithLabelValues(string(kind)).Inc()
+}
+
+func (m *Metrics) DBError(operation string) {
+	m.dbErrors.WithLabelValues(operation).Inc()
+}
+
+// statusWriter remembers the status code that was written, so it can be
+// reported after the request was handled
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+// routeName maps a request path on a fixed set of labels to prevent
+// arbitrary paths from creating new time series
+func routeName(p string) string {
+	head, _ := ShiftPath(p)
+	switch head {
+	case "":
+		return "index"
+	case "sync", "admin", "metrics", "healthz", "readyz":
+		return head
+	default:
+		return "unknown"
+	}
+}
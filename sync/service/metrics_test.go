@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-mod.ewintr.nl/planner/item"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	apiKey := "test"
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mem := NewMemory()
+	for _, i := range []item.Item{
+		{ID: "id-1", Kind: item.KindTask, Body: "body"},
+		{ID: "id-2", Kind: item.KindTask, Body: "body"},
+		{ID: "id-3", Kind: item.KindSchedule, Body: "body"},
+		{
+			ID:        "id-4",
+			Kind:      item.KindTask,
+			Recurrer:  item.NewRecurrer("2024-01-01, daily"),
+			RecurNext: item.NewDate(2024, 1, 1),
+			Body:      "body",
+		},
+	} {
+		if err := mem.Update(i, time.Now()); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+	}
+	metrics := NewMetrics()
+	rec := NewRecur(mem, mem, 8, time.UTC, metrics, logger)
+	srv := NewServer(mem, rec, apiKey, NewHealth(mem, rec, time.Hour), metrics, logger)
+
+	for _, r := range []struct {
+		url string
+		key string
+	}{
+		{url: "/sync", key: apiKey},
+		{url: "/sync", key: "wrong"},
+		{url: "/some/random/path", key: apiKey},
+	} {
+		req, err := http.NewRequest(http.MethodGet, r.url, nil)
+		if err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.key))
+		srv.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if err := rec.Recur(item.NewDate(2024, 1, 2)); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+	if res.Result().StatusCode != http.StatusOK {
+		t.Errorf("exp %v, got %v", http.StatusOK, res.Result().StatusCode)
+	}
+	body, err := io.ReadAll(res.Result().Body)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	for _, exp := range []string{
+		`plannersync_http_requests_total{route="sync",status="200"} 1`,
+		`plannersync_http_requests_total{route="sync",status="401"} 1`,
+		`plannersync_http_requests_total{route="unknown",status="404"} 1`,
+		`plannersync_items_served_total{kind="schedule"} 1`,
+		`plannersync_items_served_total{kind="task"} 3`,
+		`plannersync_recur_runs_total 1`,
+		`plannersync_recur_failures_total 0`,
+		`plannersync_recur_spawned_total{kind="task"} 2`,
+	} {
+		if !strings.Contains(string(body), exp) {
+			t.Errorf("exp metrics to contain %q", exp)
+		}
+	}
+}
@@ -28,6 +28,8 @@ var migrations = []string{
 	    ALTER COLUMN recur_next SET DEFAULT ''`,
 	`ALTER TABLE items ADD COLUMN date TEXT NOT NULL DEFAULT ''`,
 	`UPDATE items SET kind='task'`,
+	`ALTER TABLE items ADD COLUMN recur_parent TEXT NOT NULL DEFAULT ''`,
+	`CREATE INDEX idx_items_recur_parent ON items(recur_parent)`,
 }
 
 var (
@@ -63,6 +65,36 @@ func NewPostgres(host, port, dbname, user, password string) (*Postgres, error) {
 }
 
 func (p *Postgres) Update(i item.Item, ts time.Time) error {
+	return update(p.db, i, ts)
+}
+
+// UpdateMany stores all items in one transaction, either all of them are
+// persisted or none
+func (p *Postgres) UpdateMany(items []item.Item, ts time.Time) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPostgresFailure, err)
+	}
+	defer tx.Rollback()
+
+	for _, i := range items {
+		if err := update(tx, i, ts); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: %v", ErrPostgresFailure, err)
+	}
+
+	return nil
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func update(db execer, i item.Item, ts time.Time) error {
 	if i.Recurrer != nil && i.RecurNext.IsZero() {
 		i.RecurNext = i.Recurrer.First()
 	}
@@ -70,9 +102,9 @@ func (p *Postgres) Update(i item.Item, ts time.Time) error {
 	if i.Recurrer != nil {
 		recurStr = i.Recurrer.String()
 	}
-	if _, err := p.db.Exec(`
-		INSERT INTO items (id, kind, updated, deleted, date, recurrer, recur_next, body)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	if _, err := db.Exec(`
+		INSERT INTO items (id, kind, updated, deleted, date, recurrer, recur_next, recur_parent, body)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		ON CONFLICT (id) DO UPDATE
 		SET kind = EXCLUDED.kind,
 			updated = EXCLUDED.updated,
@@ -80,8 +112,9 @@ func (p *Postgres) Update(i item.Item, ts time.Time) error {
 			date = EXCLUDED.date,
 			recurrer = EXCLUDED.recurrer,
 			recur_next = EXCLUDED.recur_next,
+			recur_parent = EXCLUDED.recur_parent,
 			body = EXCLUDED.body`,
-		i.ID, i.Kind, ts, i.Deleted, i.Date.String(), recurStr, i.RecurNext.String(), i.Body); err != nil {
+		i.ID, i.Kind, ts, i.Deleted, i.Date.String(), recurStr, i.RecurNext.String(), i.RecurParent, i.Body); err != nil {
 		return fmt.Errorf("%w: %v", ErrPostgresFailure, err)
 	}
 	return nil
@@ -89,7 +122,7 @@ func (p *Postgres) Update(i item.Item, ts time.Time) error {
 
 func (p *Postgres) Updated(ks []item.Kind, t time.Time) ([]item.Item, error) {
 	query := `
-		SELECT id, kind, updated, deleted, date, recurrer, recur_next, body
+		SELECT id, kind, updated, deleted, date, recurrer, recur_next, recur_parent, body
 		FROM items
 		WHERE updated > $1`
 	args := []interface{}{t}
@@ -112,7 +145,7 @@ func (p *Postgres) Updated(ks []item.Kind, t time.Time) ([]item.Item, error) {
 	for rows.Next() {
 		var i item.Item
 		var date, recurrer, recurNext string
-		if err := rows.Scan(&i.ID, &i.Kind, &i.Updated, &i.Deleted, &date, &recurrer, &recurNext, &i.Body); err != nil {
+		if err := rows.Scan(&i.ID, &i.Kind, &i.Updated, &i.Deleted, &date, &recurrer, &recurNext, &i.RecurParent, &i.Body); err != nil {
 			return nil, fmt.Errorf("%w: %v", ErrPostgresFailure, err)
 		}
 		i.Date = item.NewDateFromString(date)
@@ -126,7 +159,7 @@ func (p *Postgres) Updated(ks []item.Kind, t time.Time) ([]item.Item, error) {
 
 func (p *Postgres) ShouldRecur(date item.Date) ([]item.Item, error) {
 	query := `
-		SELECT id, kind, updated, deleted, date, recurrer, recur_next, body
+		SELECT id, kind, updated, deleted, date, recurrer, recur_next, recur_parent, body
 		FROM items
 		WHERE
 		  NOT deleted 
@@ -142,7 +175,7 @@ func (p *Postgres) ShouldRecur(date item.Date) ([]item.Item, error) {
 	for rows.Next() {
 		var i item.Item
 		var date, recurrer, recurNext string
-		if err := rows.Scan(&i.ID, &i.Kind, &i.Updated, &i.Deleted, &date, &recurrer, &recurNext, &i.Body); err != nil {
+		if err := rows.Scan(&i.ID, &i.Kind, &i.Updated, &i.Deleted, &date, &recurrer, &recurNext, &i.RecurParent, &i.Body); err != nil {
 			return nil, fmt.Errorf("%w: %v", ErrPostgresFailure, err)
 		}
 		i.Date = item.NewDateFromString(date)
@@ -154,6 +187,50 @@ func (p *Postgres) ShouldRecur(date item.Date) ([]item.Item, error) {
 	return result, nil
 }
 
+func (p *Postgres) Instances(parentID string) ([]item.Item, error) {
+	query := `
+		SELECT id, kind, updated, deleted, date, recurrer, recur_next, recur_parent, body
+		FROM items
+		WHERE recur_parent = $1`
+	rows, err := p.db.Query(query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPostgresFailure, err)
+	}
+	defer rows.Close()
+
+	result := make([]item.Item, 0)
+	for rows.Next() {
+		var i item.Item
+		var date, recurrer, recurNext string
+		if err := rows.Scan(&i.ID, &i.Kind, &i.Updated, &i.Deleted, &date, &recurrer, &recurNext, &i.RecurParent, &i.Body); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPostgresFailure, err)
+		}
+		i.Date = item.NewDateFromString(date)
+		i.Recurrer = item.NewRecurrer(recurrer)
+		i.RecurNext = item.NewDateFromString(recurNext)
+		result = append(result, i)
+	}
+
+	return result, nil
+}
+
+func (p *Postgres) Ping() error {
+	if err := p.db.Ping(); err != nil {
+		return fmt.Errorf("%w: %v", ErrPostgresFailure, err)
+	}
+
+	return nil
+}
+
+func (p *Postgres) MigrationVersion() (int, int, error) {
+	var current int
+	if err := p.db.QueryRow(`SELECT COUNT(*) FROM migration`).Scan(&current); err != nil {
+		return 0, 0, fmt.Errorf("%w: %v", ErrPostgresFailure, err)
+	}
+
+	return current, len(migrations), nil
+}
+
 func (p *Postgres) migrate(wanted []string) error {
 	// Create migration table if not exists
 	_, err := p.db.Exec(`
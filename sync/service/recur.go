@@ -2,47 +2,120 @@ package main
 
 import (
 	"log/slog"
+	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"go-mod.ewintr.nl/planner/item"
 )
 
 type Recur struct {
-	repoSync  Syncer
-	repoRecur Recurrer
-	days      int
-	logger    *slog.Logger
+	repoSync    Syncer
+	repoRecur   Recurrer
+	days        int
+	loc         *time.Location
+	created     time.Time
+	lastSuccess time.Time
+	mutex       sync.RWMutex
+	runMutex    sync.Mutex
+	metrics     *Metrics
+	logger      *slog.Logger
 }
 
-func NewRecur(repoRecur Recurrer, repoSync Syncer, logger *slog.Logger) *Recur {
+func NewRecur(repoRecur Recurrer, repoSync Syncer, days int, loc *time.Location, metrics *Metrics, logger *slog.Logger) *Recur {
 	r := &Recur{
 		repoRecur: repoRecur,
 		repoSync:  repoSync,
+		days:      days,
+		loc:       loc,
+		created:   time.Now(),
+		metrics:   metrics,
 		logger:    logger,
 	}
 
 	return r
 }
 
-func (r *Recur) Run(days int, interval time.Duration) {
-	r.days = days
-	ticker := time.NewTicker(interval)
-
-	for range ticker.C {
-		today := item.NewDateFromString(time.Now().Format(item.DateFormat))
-		last := today.Add(r.days)
-		if err := r.Recur(last); err != nil {
+// Run recurs once right away and after that on each of the given times of
+// the day, in the timezone of the recurrer
+func (r *Recur) Run(times []item.Time) {
+	for {
+		if _, err := r.RunNow(); err != nil {
 			r.logger.Error("could not recur", "error", err)
 		}
+		next := NextRun(time.Now(), times, r.loc)
+		r.logger.Info("scheduled next recurrence run", "at", next.Format(time.RFC3339))
+		time.Sleep(time.Until(next))
 	}
 }
 
+// RunNow recurs all items up until the configured amount of days after today
+func (r *Recur) RunNow() (item.Date, error) {
+	until := r.Today().Add(r.days)
+
+	return until, r.Recur(until)
+}
+
+// Today is the current date in the timezone of the recurrer
+func (r *Recur) Today() item.Date {
+	return item.TodayIn(r.loc)
+}
+
+// NextRun finds the first moment after now that matches one of the times of
+// the day in loc
+func NextRun(now time.Time, times []item.Time, loc *time.Location) time.Time {
+	now = now.In(loc)
+	var next time.Time
+	for _, t := range times {
+		year, month, day := now.Date()
+		cand := time.Date(year, month, day, t.Hour(), t.Minute(), 0, 0, loc)
+		if !cand.After(now) {
+			cand = time.Date(year, month, day+1, t.Hour(), t.Minute(), 0, 0, loc)
+		}
+		if next.IsZero() || cand.Before(next) {
+			next = cand
+		}
+	}
+
+	return next
+}
+
 func (r *Recur) Recur(until item.Date) error {
+	r.runMutex.Lock()
+	defer r.runMutex.Unlock()
+
+	r.metrics.RecurStarted()
+	if err := r.recur(until); err != nil {
+		r.metrics.RecurFailed()
+		return err
+	}
+	now := time.Now()
+	r.mutex.Lock()
+	r.lastSuccess = now
+	r.mutex.Unlock()
+	r.metrics.RecurSucceeded(now)
+
+	return nil
+}
+
+// LastSuccess returns the time the last successful run finished and false if
+// there was none yet
+func (r *Recur) LastSuccess() (time.Time, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.lastSuccess, !r.lastSuccess.IsZero()
+}
+
+func (r *Recur) Created() time.Time {
+	return r.created
+}
+
+func (r *Recur) recur(until item.Date) error {
 	r.logger.Info("start looking for recurring items", "until", until.String())
 
 	items, err := r.repoRecur.ShouldRecur(until)
 	if err != nil {
+		r.metrics.DBError("should_recur")
 		return err
 	}
 
@@ -50,34 +123,162 @@ func (r *Recur) Recur(until item.Date) error {
 	for _, i := range items {
 		r.logger.Info("processing recurring item", "id", i.ID)
 		newRecurNext := i.RecurNext
+		if !newRecurNext.IsZero() && !i.Recurrer.RecursOn(newRecurNext) {
+			// an exception was added after RecurNext was set
+			newRecurNext = i.Recurrer.Next(newRecurNext)
+		}
 
-		for {
-			// spawn instance
-			newItem := i
-			newItem.ID = uuid.New().String()
-			newItem.Date = newRecurNext
-			newItem.Recurrer = nil
-			newItem.RecurNext = item.Date{}
-			if err := r.repoSync.Update(newItem, time.Now()); err != nil {
-				return err
-			}
-			r.logger.Info("spawned instance", "newID", newItem.ID, "date", newItem.Date)
-
-			newRecurNext = item.FirstRecurAfter(i.Recurrer, newRecurNext)
+		// a date can come around again, e.g. when RecurNext was reset. The
+		// instance spawned then may have been changed or done since, so
+		// leave it alone
+		existing, err := r.repoRecur.Instances(i.ID)
+		if err != nil {
+			r.metrics.DBError("instances")
+			return err
+		}
+		known := make(map[string]bool)
+		for _, inst := range existing {
+			known[inst.ID] = true
+		}
 
-			if newRecurNext.After(until) {
-				break
+		instances := make([]item.Item, 0)
+		for ; !newRecurNext.IsZero() && !newRecurNext.After(until); newRecurNext = i.Recurrer.Next(newRecurNext) {
+			if known[item.InstanceID(i.ID, newRecurNext)] {
+				continue
 			}
+			instances = append(instances, instance(i, newRecurNext))
 		}
 
-		// update recurrer
+		// store the instances and the updated recurrer together, so a
+		// failure halfway does not leave instances behind without moving
+		// RecurNext forward
 		i.RecurNext = newRecurNext
-		if err := r.repoSync.Update(i, time.Now()); err != nil {
+		if newRecurNext.IsZero() {
+			// the recurrence has ended, retire the recurring item
+			i.Deleted = true
+			r.logger.Info("retiring recurring item", "id", i.ID)
+		}
+		if err := r.repoSync.UpdateMany(append(instances, i), time.Now()); err != nil {
+			r.metrics.DBError("update")
 			return err
 		}
+		for _, inst := range instances {
+			r.metrics.Spawned(inst.Kind)
+			r.logger.Info("spawned instance", "newID", inst.ID, "date", inst.Date)
+		}
 		r.logger.Info("recurring item processed", "id", i.ID, "recurNext", i.RecurNext.String())
 	}
 	r.logger.Info("processed recurring items", "count", len(items))
 
 	return nil
 }
+
+// Rewrite stores a changed or deleted recurring item and applies the change
+// to the instances it already spawned on or after parent.RewriteFrom. Those
+// that still recur get the new contents, the others are deleted. Dates that
+// only recur under a new recurrer get spawned up to the usual horizon.
+// Instances that were deleted, e.g. because they were done, stay deleted.
+func (r *Recur) Rewrite(parent item.Item) error {
+	r.runMutex.Lock()
+	defer r.runMutex.Unlock()
+
+	from := parent.RewriteFrom
+	parent.RewriteFrom = item.Date{}
+	r.logger.Info("rewriting instances", "id", parent.ID, "from", from.String(), "deleted", parent.Deleted)
+
+	existing, err := r.repoRecur.Instances(parent.ID)
+	if err != nil {
+		r.metrics.DBError("instances")
+		return err
+	}
+
+	recurs := !parent.Deleted && parent.Recurrer != nil
+	known := make(map[string]bool)
+	updates := make([]item.Item, 0)
+	for _, inst := range existing {
+		known[inst.ID] = true
+		if inst.Deleted || from.After(inst.Date) {
+			continue
+		}
+		if recurs && parent.Recurrer.RecursOn(inst.Date) {
+			fresh := instance(parent, inst.Date)
+			inst.Kind = fresh.Kind
+			inst.Body = fresh.Body
+		} else {
+			inst.Deleted = true
+		}
+		updates = append(updates, inst)
+	}
+
+	spawned := make([]item.Item, 0)
+	if recurs {
+		until := r.Today().Add(r.days)
+		start := from
+		if today := r.Today(); today.After(start) {
+			start = today
+		}
+		next := parent.Recurrer.Next(start.Add(-1))
+		for ; !next.IsZero() && !next.After(until); next = parent.Recurrer.Next(next) {
+			if known[item.InstanceID(parent.ID, next)] {
+				continue
+			}
+			spawned = append(spawned, instance(parent, next))
+		}
+		parent.RecurNext = next
+		if next.IsZero() {
+			parent.Deleted = true
+			r.logger.Info("retiring recurring item", "id", parent.ID)
+		}
+	}
+
+	updates = append(updates, spawned...)
+	if err := r.repoSync.UpdateMany(append(updates, parent), time.Now()); err != nil {
+		r.metrics.DBError("update")
+		return err
+	}
+	for _, inst := range spawned {
+		r.metrics.Spawned(inst.Kind)
+	}
+	r.logger.Info("rewrote instances", "id", parent.ID, "count", len(updates))
+
+	return nil
+}
+
+// instance creates the item that parent spawns on date. A task starts out
+// fresh: it is created on that date, is not postponed or snoozed, and its
+// deadline is as far from the date as that of the parent is from the start.
+// A body that cannot be read as a task is copied as it is.
+func instance(parent item.Item, date item.Date) item.Item {
+	inst := parent
+	inst.ID = item.InstanceID(parent.ID, date)
+	inst.Date = date
+	inst.Recurrer = nil
+	inst.RecurNext = item.Date{}
+	inst.RecurParent = parent.ID
+	inst.RewriteFrom = item.Date{}
+
+	tsk, err := item.NewTask(inst)
+	if err != nil {
+		return inst
+	}
+	if !tsk.Deadline.IsZero() {
+		start := parent.Date
+		if start.IsZero() && parent.Recurrer != nil {
+			start = parent.Recurrer.First()
+		}
+		days := start.DaysBetween(tsk.Deadline)
+		if start.After(tsk.Deadline) {
+			days = -days
+		}
+		tsk.Deadline = date.Add(days)
+	}
+	tsk.Created = date
+	tsk.Postponed = 0
+	tsk.RolledOver = item.Date{}
+	tsk.SnoozeUntil = item.Date{}
+	if fresh, err := tsk.Item(); err == nil {
+		inst.Body = fresh.Body
+	}
+
+	return inst
+}
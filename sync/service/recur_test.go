@@ -1,11 +1,15 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"sort"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"go-mod.ewintr.nl/planner/item"
 )
 
@@ -16,7 +20,7 @@ func TestRecur(t *testing.T) {
 	today := item.NewDate(2024, 1, 1)
 	until := today.Add(3)
 	mem := NewMemory()
-	rec := NewRecur(mem, mem, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	rec := NewRecur(mem, mem, 8, time.UTC, NewMetrics(), slog.New(slog.NewTextHandler(io.Discard, nil)))
 
 	testItem := item.Item{
 		ID:        "test-1",
@@ -47,6 +51,14 @@ func TestRecur(t *testing.T) {
 	if len(items) != 5 { // Original + 4 new instances
 		t.Errorf("expected 5 items, got %d", len(items))
 	}
+	for _, i := range items {
+		if i.ID == testItem.ID {
+			continue
+		}
+		if i.RecurParent != testItem.ID {
+			t.Errorf("exp %v, got %v", testItem.ID, i.RecurParent)
+		}
+	}
 
 	// Check that RecurNext was updated
 	recurItems, err := mem.ShouldRecur(until.Add(1))
@@ -60,3 +72,439 @@ func TestRecur(t *testing.T) {
 		t.Errorf("RecurNext was not updated, still %v", recurItems[0].RecurNext)
 	}
 }
+
+var errCrash = errors.New("crash")
+
+// crashRepo stops writing after a fixed number of items, as if the service
+// died halfway. Items are written one by one, so it also covers a storage
+// that does not honour the transaction.
+type crashRepo struct {
+	*Memory
+	writesLeft int
+}
+
+func (cr *crashRepo) UpdateMany(items []item.Item, ts time.Time) error {
+	for _, i := range items {
+		if cr.writesLeft == 0 {
+			return errCrash
+		}
+		cr.writesLeft--
+		if err := cr.Memory.Update(i, ts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestRecurCrash(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	until := item.NewDate(2024, 1, 10)
+	recurring := []item.Item{
+		{
+			ID:        "daily",
+			Kind:      item.KindTask,
+			Recurrer:  item.NewRecurrer("2024-01-01, daily"),
+			RecurNext: item.NewDate(2024, 1, 1),
+			Body:      `{"title":"daily"}`,
+		},
+		{
+			ID:        "weekly",
+			Kind:      item.KindTask,
+			Recurrer:  item.NewRecurrer("2024-01-01, weekly, monday & thursday"),
+			RecurNext: item.NewDate(2024, 1, 1),
+			Body:      `{"title":"weekly"}`,
+		},
+	}
+	setup := func() *Memory {
+		mem := NewMemory()
+		for _, i := range recurring {
+			if err := mem.Update(i, time.Now()); err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+		}
+		return mem
+	}
+
+	// 10 daily instances, 3 weekly instances and the two recurring items
+	expTotal := 15
+	for writes := 0; writes <= expTotal; writes++ {
+		t.Run(fmt.Sprintf("crash after %d writes", writes), func(t *testing.T) {
+			mem := setup()
+			crash := &crashRepo{Memory: mem, writesLeft: writes}
+			if err := NewRecur(crash, crash, 8, time.UTC, NewMetrics(), logger).Recur(until); err != nil && !errors.Is(err, errCrash) {
+				t.Errorf("exp nil or crash, got %v", err)
+			}
+			if err := NewRecur(mem, mem, 8, time.UTC, NewMetrics(), logger).Recur(until); err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+
+			items, err := mem.Updated(nil, time.Time{})
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if len(items) != expTotal {
+				t.Errorf("exp %d, got %d", expTotal, len(items))
+			}
+			seen := make(map[string]bool)
+			for _, i := range items {
+				if i.Recurrer != nil {
+					if !i.RecurNext.After(until) {
+						t.Errorf("exp recur next after %s, got %s", until, i.RecurNext)
+					}
+					continue
+				}
+				key := fmt.Sprintf("%s %s", i.Body, i.Date)
+				if seen[key] {
+					t.Errorf("duplicate instance %s", key)
+				}
+				seen[key] = true
+			}
+		})
+	}
+}
+
+func TestRecurRewrite(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	today := item.TodayIn(time.UTC)
+	start := today.Add(-5)
+	parent := item.Item{
+		ID:        "parent",
+		Kind:      item.KindTask,
+		Recurrer:  item.NewRecurrer(fmt.Sprintf("%s, daily", start)),
+		RecurNext: start,
+		Body:      "old",
+	}
+
+	for _, tc := range []struct {
+		name    string
+		change  func(i item.Item) item.Item
+		expDays map[int]string
+	}{
+		{
+			name: "change contents from today",
+			change: func(i item.Item) item.Item {
+				i.Body = "new"
+				i.RewriteFrom = today
+				return i
+			},
+			expDays: map[int]string{
+				-5: "old", -4: "old", -3: "old", -2: "old", -1: "old",
+				0: "new", 1: "deleted", 2: "new", 3: "new",
+				4: "new", 5: "new", 6: "new", 7: "new", 8: "new",
+			},
+		},
+		{
+			name: "delete this and future",
+			change: func(i item.Item) item.Item {
+				i.Deleted = true
+				i.RewriteFrom = today.Add(2)
+				return i
+			},
+			expDays: map[int]string{
+				-5: "old", -4: "old", -3: "old", -2: "old", -1: "old",
+				0: "old", 1: "deleted", 2: "deleted", 3: "deleted",
+			},
+		},
+		{
+			name: "change recurrer of entire series",
+			change: func(i item.Item) item.Item {
+				i.Body = "new"
+				i.Recurrer = item.NewRecurrer(fmt.Sprintf("%s, every 2 days", start))
+				i.RewriteFrom = start
+				return i
+			},
+			expDays: map[int]string{
+				-5: "new", -4: "deleted", -3: "new", -2: "deleted", -1: "new",
+				0: "deleted", 1: "deleted", 2: "deleted", 3: "new",
+				5: "new", 7: "new",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := NewMemory()
+			rec := NewRecur(mem, mem, 8, time.UTC, NewMetrics(), logger)
+			if err := mem.Update(parent, time.Now()); err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			if err := rec.Recur(today.Add(3)); err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			done := instance(parent, today.Add(1))
+			done.Deleted = true
+			if err := mem.Update(done, time.Now()); err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+
+			if err := rec.Rewrite(tc.change(parent)); err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+
+			instances, err := mem.Instances(parent.ID)
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			actDays := make(map[int]string)
+			for _, inst := range instances {
+				state := inst.Body
+				if inst.Deleted {
+					state = "deleted"
+				}
+				offset := today.DaysBetween(inst.Date)
+				if today.After(inst.Date) {
+					offset = -offset
+				}
+				actDays[offset] = state
+			}
+			if diff := cmp.Diff(tc.expDays, actDays); diff != "" {
+				t.Errorf("(exp +, got -)\n%s", diff)
+			}
+			items, err := mem.Updated(nil, time.Time{})
+			if err != nil {
+				t.Errorf("exp nil, got %v", err)
+			}
+			for _, i := range items {
+				if i.ID == parent.ID && !i.RewriteFrom.IsZero() {
+					t.Errorf("exp empty rewrite date, got %v", i.RewriteFrom)
+				}
+			}
+		})
+	}
+}
+
+func TestRecurKnownInstances(t *testing.T) {
+	t.Parallel()
+
+	today := item.NewDate(2024, 1, 1)
+	mem := NewMemory()
+	rec := NewRecur(mem, mem, 8, time.UTC, NewMetrics(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	parent := item.Item{
+		ID:        "parent",
+		Kind:      item.KindTask,
+		Recurrer:  item.NewRecurrer("2024-01-01, daily"),
+		RecurNext: today,
+		Body:      "old",
+	}
+	if err := mem.Update(parent, time.Now()); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if err := rec.Recur(today.Add(1)); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	edited := instance(parent, today)
+	edited.Body = "edited"
+	done := instance(parent, today.Add(1))
+	done.Deleted = true
+	// a reset RecurNext brings the same dates around again
+	if err := mem.UpdateMany([]item.Item{edited, done, parent}, time.Now()); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if err := rec.Recur(today.Add(2)); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	instances, err := mem.Instances(parent.ID)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	act := make(map[string]string)
+	for _, inst := range instances {
+		state := inst.Body
+		if inst.Deleted {
+			state = "deleted"
+		}
+		act[inst.Date.String()] = state
+	}
+	exp := map[string]string{
+		"2024-01-01": "edited",
+		"2024-01-02": "deleted",
+		"2024-01-03": "old",
+	}
+	if diff := cmp.Diff(exp, act); diff != "" {
+		t.Errorf("(exp -, got +)\n%s", diff)
+	}
+}
+
+func TestInstance(t *testing.T) {
+	t.Parallel()
+
+	parent, err := item.Task{
+		ID:        "parent",
+		Date:      item.NewDate(2024, 1, 1),
+		Recurrer:  item.NewRecurrer("2024-01-01, weekly, monday"),
+		RecurNext: item.NewDate(2024, 1, 1),
+		TaskBody: item.TaskBody{
+			Title:       "report",
+			Created:     item.NewDate(2023, 12, 1),
+			Deadline:    item.NewDate(2024, 1, 3),
+			SnoozeUntil: item.NewDate(2023, 12, 20),
+			Postponed:   2,
+			Tags:        []string{"work"},
+		},
+	}.Item()
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	date := item.NewDate(2024, 1, 15)
+	act, err := item.NewTask(instance(parent, date))
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	exp := item.Task{
+		ID:          item.InstanceID("parent", date),
+		Date:        date,
+		RecurParent: "parent",
+		TaskBody: item.TaskBody{
+			Title:    "report",
+			Created:  date,
+			Deadline: item.NewDate(2024, 1, 17),
+			Tags:     []string{"work"},
+		},
+	}
+	if diff := item.TaskDiff(exp, act); diff != "" {
+		t.Errorf("(exp -, got +)\n%s", diff)
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	t.Parallel()
+
+	ams, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("exp nil, got %v", err)
+	}
+	times := []item.Time{item.NewTime(0, 5), item.NewTime(12, 0)}
+
+	for _, tc := range []struct {
+		name  string
+		now   time.Time
+		times []item.Time
+		exp   time.Time
+	}{
+		{
+			name:  "later today",
+			now:   time.Date(2024, 12, 1, 8, 0, 0, 0, ams),
+			times: times,
+			exp:   time.Date(2024, 12, 1, 12, 0, 0, 0, ams),
+		},
+		{
+			name:  "tomorrow",
+			now:   time.Date(2024, 12, 1, 13, 0, 0, 0, ams),
+			times: times,
+			exp:   time.Date(2024, 12, 2, 0, 5, 0, 0, ams),
+		},
+		{
+			name:  "exactly on time",
+			now:   time.Date(2024, 12, 1, 12, 0, 0, 0, ams),
+			times: times,
+			exp:   time.Date(2024, 12, 2, 0, 5, 0, 0, ams),
+		},
+		{
+			name:  "now in other zone",
+			now:   time.Date(2024, 12, 1, 23, 30, 0, 0, time.UTC),
+			times: []item.Time{item.NewTime(0, 5)},
+			exp:   time.Date(2024, 12, 3, 0, 5, 0, 0, ams),
+		},
+		{
+			name:  "dst change",
+			now:   time.Date(2024, 3, 30, 12, 0, 0, 0, ams),
+			times: []item.Time{item.NewTime(0, 5)},
+			exp:   time.Date(2024, 3, 31, 0, 5, 0, 0, ams),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			act := NextRun(tc.now, tc.times, ams)
+			if !tc.exp.Equal(act) {
+				t.Errorf("exp %v, got %v", tc.exp, act)
+			}
+		})
+	}
+}
+
+func TestRecurEnding(t *testing.T) {
+	t.Parallel()
+
+	mem := NewMemory()
+	rec := NewRecur(mem, mem, 8, time.UTC, NewMetrics(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	parent := item.Item{
+		ID:        "parent",
+		Kind:      item.KindTask,
+		Recurrer:  item.NewRecurrer("2024-01-01, daily, 3 times"),
+		RecurNext: item.NewDate(2024, 1, 1),
+		Body:      `{"title":"three times"}`,
+	}
+	if err := mem.Update(parent, time.Now()); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	for _, until := range []item.Date{item.NewDate(2024, 1, 2), item.NewDate(2024, 1, 10)} {
+		if err := rec.Recur(until); err != nil {
+			t.Errorf("exp nil, got %v", err)
+		}
+	}
+
+	instances, err := mem.Instances(parent.ID)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if len(instances) != 3 {
+		t.Errorf("exp 3, got %d", len(instances))
+	}
+	items, err := mem.Updated(nil, time.Time{})
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	for _, i := range items {
+		if i.ID == parent.ID && !i.Deleted {
+			t.Errorf("exp retired recurring item, got %v", i)
+		}
+	}
+	recurring, err := mem.ShouldRecur(item.NewDate(2024, 2, 1))
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	if len(recurring) != 0 {
+		t.Errorf("exp 0, got %d", len(recurring))
+	}
+}
+
+func TestRecurExcept(t *testing.T) {
+	t.Parallel()
+
+	mem := NewMemory()
+	rec := NewRecur(mem, mem, 8, time.UTC, NewMetrics(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	// the exception was added after recur next was set
+	parent := item.Item{
+		ID:        "parent",
+		Kind:      item.KindTask,
+		Recurrer:  item.NewRecurrer("2024-01-01, daily, except 2024-01-01 & 2024-01-03"),
+		RecurNext: item.NewDate(2024, 1, 1),
+		Body:      `{"title":"daily"}`,
+	}
+	if err := mem.Update(parent, time.Now()); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	if err := rec.Recur(item.NewDate(2024, 1, 4)); err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+
+	instances, err := mem.Instances(parent.ID)
+	if err != nil {
+		t.Errorf("exp nil, got %v", err)
+	}
+	actDates := make([]string, 0)
+	for _, inst := range instances {
+		actDates = append(actDates, inst.Date.String())
+	}
+	sort.Strings(actDates)
+	if diff := cmp.Diff([]string{"2024-01-02", "2024-01-04"}, actDates); diff != "" {
+		t.Errorf("(exp +, got -)\n%s", diff)
+	}
+}
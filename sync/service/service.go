@@ -7,8 +7,11 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+
+	"go-mod.ewintr.nl/planner/item"
 )
 
 var (
@@ -20,11 +23,30 @@ var (
 	dbUser     = flag.String("dbuser", "test", "database user")
 	dbPassword = flag.String("dbpassword", "test", "database password")
 	recurDays  = flag.Int("recurdays", 8, "amount of days ahead to recur")
+	recurTimes = flag.String("recurtimes", "00:05", "comma separated times of the day to recur")
+	recurAge   = flag.Duration("recurmaxage", 25*time.Hour, "max time since last successful recurrence run before the service is reported not ready")
+	timezone   = flag.String("timezone", "Local", "timezone of the user, used to determine the current day")
 )
 
 func main() {
 	flag.Parse()
 
+	loc, err := time.LoadLocation(*timezone)
+	if err != nil {
+		fmt.Printf("could not load timezone: %s", err.Error())
+		os.Exit(1)
+	}
+	item.SetTimezone(loc)
+	times := make([]item.Time, 0)
+	for _, tStr := range strings.Split(*recurTimes, ",") {
+		t := item.NewTimeFromString(strings.TrimSpace(tStr))
+		if t.IsZero() {
+			fmt.Printf("could not parse recur time: %q", tStr)
+			os.Exit(1)
+		}
+		times = append(times, t)
+	}
+
 	repo, err := NewPostgres(*dbHost, *dbPort, *dbName, *dbUser, *dbPassword)
 	if err != nil {
 		fmt.Printf("could not open postgres db: %s", err.Error())
@@ -33,16 +55,20 @@ func main() {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	logger.Info("configuration", "configuration", map[string]string{
-		"port":   *apiPort,
-		"dbHost": *dbHost,
-		"dbPort": *dbPort,
-		"dbName": *dbName,
-		"dbUser": *dbUser,
+		"port":       *apiPort,
+		"dbHost":     *dbHost,
+		"dbPort":     *dbPort,
+		"dbName":     *dbName,
+		"dbUser":     *dbUser,
+		"recurTimes": *recurTimes,
+		"timezone":   loc.String(),
 	})
-	recurrer := NewRecur(repo, repo, logger)
-	go recurrer.Run(*recurDays, 6*time.Hour)
+	metrics := NewMetrics()
+	recurrer := NewRecur(repo, repo, *recurDays, loc, metrics, logger)
+	go recurrer.Run(times)
 
-	srv := NewServer(repo, *apiKey, logger)
+	health := NewHealth(repo, recurrer, *recurAge)
+	srv := NewServer(repo, recurrer, *apiKey, health, metrics, logger)
 	go http.ListenAndServe(fmt.Sprintf(":%s", *apiPort), srv)
 
 	logger.Info("service started")
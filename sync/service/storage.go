@@ -14,9 +14,16 @@ var (
 
 type Syncer interface {
 	Update(item item.Item, t time.Time) error
+	UpdateMany(items []item.Item, t time.Time) error
 	Updated(kind []item.Kind, t time.Time) ([]item.Item, error)
 }
 
+type Checker interface {
+	Ping() error
+	MigrationVersion() (current, expected int, err error)
+}
+
 type Recurrer interface {
 	ShouldRecur(date item.Date) ([]item.Item, error)
+	Instances(parentID string) ([]item.Item, error)
 }